@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	contribution "github-dashboard/pkg"
+	"github-dashboard/pkg/config"
+	"github-dashboard/pkg/github"
 	"github-dashboard/pkg/tui"
 	"io"
 	"log"
@@ -43,24 +49,136 @@ func setupFileLogger() {
 	log.Printf("UI logging initialized - %s", time.Now().Format(time.RFC3339))
 }
 
+// fatal reports an error on stderr and exits, the log is discarded without
+// debug logging.
+func fatal(err error) {
+	log.Print(err)
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
 func main() {
 	setupFileLogger()
 
+	apiURL := flag.String("api-url", contribution.GetAPIURL(), "GitHub API base URL, e.g. https://ghe.example.com/api for GitHub Enterprise Server (env GITHUB_API_URL)")
+	maxRepos := flag.Int("max-repos", 0, "maximum number of repositories to fetch (0 fetches all)")
+	cacheTTL := flag.Duration("cache-ttl", github.DefaultCacheTTL, "how long cached API responses are considered fresh")
+	noCache := flag.Bool("no-cache", false, "disable the on-disk response cache")
+	refreshInterval := flag.Duration("refresh-interval", 0, "refetch the data periodically, e.g. 5m (0 disables it)")
+	offline := flag.Bool("offline", false, "render cached data only, without querying the API")
+	configPath := flag.String("config", "", "path of the config file (default $XDG_CONFIG_HOME/github-dashboard/config.json)")
+	from := flag.String("from", "", "first day of the contribution calendar, YYYY-MM-DD (default one year before --to)")
+	to := flag.String("to", "", "last day of the contribution calendar, YYYY-MM-DD (default one year after --from or today)")
+	colorScale := flag.String("color-scale", "github", "calendar color scale: github (levels computed by GitHub), quartile or fixed")
+	color := flag.String("color", "auto", "color the output: auto (detected from the terminal, honoring NO_COLOR), always or never")
+	jsonOutput := flag.Bool("json", false, "print the contribution stats as JSON instead of starting the dashboard")
+	flag.Parse()
+
+	contributionRange, err := contribution.ParseRange(*from, *to, time.Now())
+	if err != nil {
+		fatal(err)
+	}
+	scale, err := contribution.ParseColorScale(*colorScale)
+	if err != nil {
+		fatal(err)
+	}
+	colorMode, err := contribution.ParseColorMode(*color)
+	if err != nil {
+		fatal(err)
+	}
+
+	if *configPath == "" {
+		path, err := config.DefaultPath()
+		if err != nil {
+			fatal(err)
+		}
+		*configPath = path
+	}
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal(err)
+	}
+	if err := tui.ValidateColumns(cfg.Columns); err != nil {
+		fatal(fmt.Errorf("config %s: %w", *configPath, err))
+	}
+	theme := contribution.DefaultTheme
+	if cfg.Theme != "" {
+		if theme, err = contribution.ThemeByName(cfg.Theme); err != nil {
+			fatal(fmt.Errorf("config %s: %w", *configPath, err))
+		}
+	}
+	var glyphs contribution.DayDisplay
+	if cfg.Glyphs != "" {
+		if glyphs, err = contribution.ParseDayDisplay(cfg.Glyphs); err != nil {
+			fatal(fmt.Errorf("config %s: %w", *configPath, err))
+		}
+	}
+
 	token := contribution.GetToken()
-	if token == "" {
-		log.Fatal("GITHUB_TOKEN not set")
+	if token == "" && !*offline {
+		fatal(errors.New("GITHUB_TOKEN not set"))
+	}
+
+	if flag.NArg() == 0 {
+		fatal(errors.New("No username provided"))
+	}
+	username := flag.Arg(0)
+
+	endpoint, err := github.GraphQLEndpoint(*apiURL)
+	if err != nil {
+		fatal(err)
 	}
 
-	args := os.Args[1:]
-	if len(args) == 0 {
-		log.Fatal("No username provided")
+	clientOptions := []github.ClientOption{github.WithEndpoint(endpoint)}
+	if !*noCache {
+		cacheDir, err := github.DefaultCacheDir()
+		if err != nil {
+			fatal(err)
+		}
+		clientOptions = append(clientOptions, github.WithCache(github.NewCache(cacheDir, *cacheTTL)))
+	} else if *offline {
+		fatal(errors.New("--offline requires the cache, drop --no-cache"))
 	}
-	username := args[0]
 
-	m := tui.InitModel(username)
-	p := tea.NewProgram(m)
+	client := github.NewClient(token, clientOptions...)
+	if *jsonOutput {
+		if *offline {
+			client = client.WithCachePolicy(github.CacheOnly)
+		}
+		if err := printStats(client, username, contributionRange); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	m := tui.InitModel(tui.Config{
+		Username:        username,
+		Client:          client,
+		MaxRepositories: *maxRepos,
+		Offline:         *offline,
+		RefreshInterval: *refreshInterval,
+		Columns:         cfg.Columns,
+		Range:           contributionRange,
+		ColorScale:      scale,
+		Theme:           theme,
+		Glyphs:          glyphs,
+	})
+	p := tea.NewProgram(m, tea.WithColorProfile(colorMode.Profile(os.Stdout, os.Environ())))
 	if _, err := p.Run(); err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
 }
+
+func printStats(client *github.Client, username string, r contribution.Range) error {
+	calendar, err := contribution.GetContributionsFromApi(context.Background(), client, username, r)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		contribution.Stats
+		Breakdown contribution.Breakdown `json:"breakdown"`
+	}{contribution.ComputeStats(calendar, time.Now()), calendar.Breakdown})
+}
@@ -0,0 +1,147 @@
+package contribution
+
+import (
+	"cmp"
+	"slices"
+)
+
+// breakdownFields extends the contributionsCollection selection of query, up
+// to 25 repositories are fetched for every contribution type.
+const breakdownFields = `
+                totalCommitContributions
+                totalPullRequestContributions
+                totalIssueContributions
+                totalPullRequestReviewContributions
+                restrictedContributionsCount
+                commitContributionsByRepository(maxRepositories: 25) {
+                    repository {
+                        ...contributedRepository
+                    }
+                    contributions {
+                        totalCount
+                    }
+                }
+                pullRequestContributionsByRepository(maxRepositories: 25) {
+                    repository {
+                        ...contributedRepository
+                    }
+                    contributions {
+                        totalCount
+                    }
+                }
+                issueContributionsByRepository(maxRepositories: 25) {
+                    repository {
+                        ...contributedRepository
+                    }
+                    contributions {
+                        totalCount
+                    }
+                }
+                pullRequestReviewContributionsByRepository(maxRepositories: 25) {
+                    repository {
+                        ...contributedRepository
+                    }
+                    contributions {
+                        totalCount
+                    }
+                }`
+
+const repositoryFragment = `
+fragment contributedRepository on Repository {
+    nameWithOwner
+    url
+    owner {
+        login
+    }
+}
+`
+
+// Breakdown splits the contributions of a range by type and by repository.
+type Breakdown struct {
+	Commits      int `json:"commits"`
+	PullRequests int `json:"pullRequests"`
+	Issues       int `json:"issues"`
+	Reviews      int `json:"reviews"`
+	// Restricted counts the private contributions hidden from the viewer.
+	Restricted int `json:"restricted"`
+	// Repositories are ordered by their total contributions, the busiest first.
+	Repositories []RepositoryContributions `json:"repositories"`
+}
+
+// RepositoryContributions counts the contributions to a repository, which
+// may belong to another user or an organization.
+type RepositoryContributions struct {
+	Repository   string `json:"repository"`
+	Owner        string `json:"owner"`
+	URL          string `json:"url"`
+	Commits      int    `json:"commits"`
+	PullRequests int    `json:"pullRequests"`
+	Issues       int    `json:"issues"`
+	Reviews      int    `json:"reviews"`
+}
+
+func (r RepositoryContributions) Total() int {
+	return r.Commits + r.PullRequests + r.Issues + r.Reviews
+}
+
+type repositoryContributionsResponse []struct {
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+		URL           string `json:"url"`
+		Owner         struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Contributions struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"contributions"`
+}
+
+type breakdownResponse struct {
+	TotalCommitContributions                   int                             `json:"totalCommitContributions"`
+	TotalPullRequestContributions              int                             `json:"totalPullRequestContributions"`
+	TotalIssueContributions                    int                             `json:"totalIssueContributions"`
+	TotalPullRequestReviewContributions        int                             `json:"totalPullRequestReviewContributions"`
+	RestrictedContributionsCount               int                             `json:"restrictedContributionsCount"`
+	CommitContributionsByRepository            repositoryContributionsResponse `json:"commitContributionsByRepository"`
+	PullRequestContributionsByRepository       repositoryContributionsResponse `json:"pullRequestContributionsByRepository"`
+	IssueContributionsByRepository             repositoryContributionsResponse `json:"issueContributionsByRepository"`
+	PullRequestReviewContributionsByRepository repositoryContributionsResponse `json:"pullRequestReviewContributionsByRepository"`
+}
+
+func parseBreakdown(response breakdownResponse) Breakdown {
+	breakdown := Breakdown{
+		Commits:      response.TotalCommitContributions,
+		PullRequests: response.TotalPullRequestContributions,
+		Issues:       response.TotalIssueContributions,
+		Reviews:      response.TotalPullRequestReviewContributions,
+		Restricted:   response.RestrictedContributionsCount,
+	}
+
+	index := make(map[string]int)
+	add := func(list repositoryContributionsResponse, count func(*RepositoryContributions) *int) {
+		for _, item := range list {
+			name := item.Repository.NameWithOwner
+			i, ok := index[name]
+			if !ok {
+				i = len(breakdown.Repositories)
+				index[name] = i
+				breakdown.Repositories = append(breakdown.Repositories, RepositoryContributions{
+					Repository: name,
+					Owner:      item.Repository.Owner.Login,
+					URL:        item.Repository.URL,
+				})
+			}
+			*count(&breakdown.Repositories[i]) += item.Contributions.TotalCount
+		}
+	}
+	add(response.CommitContributionsByRepository, func(r *RepositoryContributions) *int { return &r.Commits })
+	add(response.PullRequestContributionsByRepository, func(r *RepositoryContributions) *int { return &r.PullRequests })
+	add(response.IssueContributionsByRepository, func(r *RepositoryContributions) *int { return &r.Issues })
+	add(response.PullRequestReviewContributionsByRepository, func(r *RepositoryContributions) *int { return &r.Reviews })
+
+	slices.SortStableFunc(breakdown.Repositories, func(a, b RepositoryContributions) int {
+		return cmp.Or(cmp.Compare(b.Total(), a.Total()), cmp.Compare(a.Repository, b.Repository))
+	})
+	return breakdown
+}
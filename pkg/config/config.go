@@ -0,0 +1,52 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const fileName = "config.json"
+
+// Column is a column of the repository table, a zero width keeps the default width.
+type Column struct {
+	Name  string `json:"name"`
+	Width int    `json:"width,omitempty"`
+}
+
+// Config holds the user preferences read from the config file.
+type Config struct {
+	// Columns lists the columns of the repository table in order, empty keeps the defaults.
+	Columns []Column `json:"columns,omitempty"`
+	// Theme names the colors of the contribution calendar, empty keeps the classic colors.
+	Theme string `json:"theme,omitempty"`
+	// Glyphs names the glyph set of the calendar days, or lists custom glyphs.
+	Glyphs string `json:"glyphs,omitempty"`
+}
+
+// DefaultPath returns the config file location under the user config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "github-dashboard", fileName), nil
+}
+
+// Load reads the config file at path, a missing file yields the default config.
+func Load(path string) (Config, error) {
+	var config Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("config %s: %w", path, err)
+	}
+	return config, nil
+}
@@ -1,23 +1,25 @@
 package contribution
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
+
+	"github-dashboard/pkg/github"
 )
 
 const query = `
-query($username: String!) {
+query($username: String!, $from: DateTime, $to: DateTime) {` + github.RateLimitSelection + `
         user(login: $username) {
-            contributionsCollection {
+            contributionsCollection(from: $from, to: $to) {
+                contributionYears` + breakdownFields + `
                 contributionCalendar {
                     totalContributions
                     weeks {
                         contributionDays {
                             contributionCount
+                            contributionLevel
                             date
                             weekday
                         }
@@ -26,12 +28,121 @@ query($username: String!) {
             }
         }
     }
-`
+` + repositoryFragment
+
+// Range limits the contributions to a period of at most one year. The zero
+// Range stands for the API default, the last year.
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+const dateLayout = "2006-01-02"
+
+// ParseRange parses the from and to dates, both inclusive and in the
+// YYYY-MM-DD form. A missing date lies one year away from the other one.
+func ParseRange(from, to string, now time.Time) (Range, error) {
+	var r Range
+	if from == "" && to == "" {
+		return r, nil
+	}
+	if from != "" {
+		date, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", from)
+		}
+		r.From = date
+	}
+	if to != "" {
+		date, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", to)
+		}
+		r.To = endOfDay(date)
+	}
+	switch {
+	case r.From.IsZero():
+		r.From = r.To.AddDate(-1, 0, 0).Add(time.Second)
+	case r.To.IsZero():
+		r.To = r.From.AddDate(1, 0, 0).Add(-time.Second)
+	}
+	r.To = minTime(r.To, endOfDay(now.UTC()))
+	return r, r.validate()
+}
+
+// YearRange covers the calendar year, up to today for the current year.
+func YearRange(year int, now time.Time) Range {
+	return Range{
+		From: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   minTime(time.Date(year, time.December, 31, 23, 59, 59, 0, time.UTC), endOfDay(now.UTC())),
+	}
+}
+
+func (r Range) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+func (r Range) validate() error {
+	if r.To.Before(r.From) {
+		return errors.New("the date range ends before it starts")
+	}
+	if r.To.After(r.From.AddDate(1, 0, 0)) {
+		return errors.New("the date range must not exceed one year")
+	}
+	return nil
+}
+
+func (r Range) String() string {
+	if r.IsZero() {
+		return "last year"
+	}
+	return r.From.Format(dateLayout) + " – " + r.To.Format(dateLayout)
+}
+
+func (r Range) variables() map[string]interface{} {
+	variables := map[string]interface{}{}
+	if !r.IsZero() {
+		variables["from"] = r.From.Format(time.RFC3339)
+		variables["to"] = r.To.Format(time.RFC3339)
+	}
+	return variables
+}
+
+// endOfDay returns the last second of the day of t. Range ends are kept at
+// day granularity so that cached responses stay valid throughout the day.
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// Calendar holds the contributions of a range laid out by MakeContributionMatrix.
+type Calendar struct {
+	Matrix    [][]ContributionDay
+	Total     uint64
+	Breakdown Breakdown
+	// Years lists the years the user contributed in, most recent first.
+	Years []int
+}
 
 type ContributionDay struct {
-	ContributionCount uint64 `json:"contributionCount"`
-	Month             uint8  `json:"date"`
-	Weekday           uint8  `json:"weekday"`
+	ContributionCount uint64    `json:"contributionCount"`
+	Month             uint8     `json:"month"`
+	Weekday           uint8     `json:"weekday"`
+	Date              time.Time `json:"date"`
+	// Level is the intensity computed by GitHub, from 0 up to 4.
+	Level uint8 `json:"level"`
+}
+
+// IsPadding reports whether the day only fills the partial first or last week
+// of the calendar.
+func (c *ContributionDay) IsPadding() bool {
+	return c.Date.IsZero()
 }
 
 var MonthAbreviations = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
@@ -47,94 +158,85 @@ func (c *ContributionDay) GetPreviousMonthAbreviation() string {
 	return MonthAbreviations[c.Month-1]
 }
 
-func parseContributions(raw map[string]interface{}) ([]ContributionDay, error) {
-	raw, ok := raw["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected 'data' keyword, got %T", raw["data"])
-	}
-	raw, ok = raw["user"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected 'user', got %T", raw["user"])
-	}
-	raw, ok = raw["contributionsCollection"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected 'contributionsCollection', got %T", raw["contributionsCollection"])
-	}
-	raw, ok = raw["contributionCalendar"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected 'contributionsCalendar', got %T", raw["contributionCalendar"])
+type contributionsResponse struct {
+	User *struct {
+		ContributionsCollection struct {
+			breakdownResponse
+			ContributionYears    []int `json:"contributionYears"`
+			ContributionCalendar struct {
+				TotalContributions uint64 `json:"totalContributions"`
+				Weeks              []struct {
+					ContributionDays []struct {
+						ContributionCount uint64 `json:"contributionCount"`
+						ContributionLevel string `json:"contributionLevel"`
+						Date              string `json:"date"`
+						Weekday           uint8  `json:"weekday"`
+					} `json:"contributionDays"`
+				} `json:"weeks"`
+			} `json:"contributionCalendar"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+func parseContributions(response contributionsResponse, username string) ([]ContributionDay, error) {
+	if response.User == nil {
+		return nil, &github.UserNotFoundError{Login: username}
 	}
 	var contributions []ContributionDay
-	for _, week := range raw["weeks"].([]interface{}) {
-		for _, day := range week.(map[string]interface{})["contributionDays"].([]interface{}) {
-			date := day.(map[string]interface{})["date"].(string)
-			month, err := time.Parse("2006-01-02", date)
+	for _, week := range response.User.ContributionsCollection.ContributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			date, err := time.Parse("2006-01-02", day.Date)
 			if err != nil {
 				return nil, err
 			}
 			contributions = append(contributions, ContributionDay{
-				ContributionCount: uint64(day.(map[string]interface{})["contributionCount"].(float64)),
-				Month:             uint8(month.Month()) - 1,
-				Weekday:           uint8(day.(map[string]interface{})["weekday"].(float64)),
+				ContributionCount: day.ContributionCount,
+				Month:             uint8(date.Month()) - 1,
+				Weekday:           day.Weekday,
+				Date:              date,
+				Level:             contributionLevels[day.ContributionLevel],
 			})
 		}
 	}
 	return contributions, nil
 }
 
+// MakeContributionMatrix lays consecutive days out in a weekday x week grid.
+// The partial first and last weeks are filled with padding days so that every
+// row has the same length.
 func MakeContributionMatrix(contributions []ContributionDay) [][]ContributionDay {
 	matrix := make([][]ContributionDay, 7)
-	for _, contribution := range contributions {
-
-		row := matrix[contribution.Weekday]
-		if row == nil {
-			row = make([]ContributionDay, 0)
-		}
-		row = append(row, contribution)
-		matrix[contribution.Weekday] = row
-	}
-	return matrix
-}
-
-func GetContributionsFromApi(token, username string) ([][]ContributionDay, error) {
-	requestBody := map[string]interface{}{
-		"query": query,
-		"variables": map[string]interface{}{
-			"username": username,
-		},
-	}
-	body, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, err
+	if len(contributions) == 0 {
+		return matrix
 	}
-	req, err := http.NewRequest("POST", "https://api.github.com/graphql", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
+	first := int(contributions[0].Weekday)
+	weeks := (first + len(contributions) + 6) / 7
+	for weekday := range matrix {
+		matrix[weekday] = make([]ContributionDay, weeks)
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("non 200; status: %d body: %s", resp.StatusCode, string(bodyBytes))
+	for i, contribution := range contributions {
+		matrix[contribution.Weekday][(first+i)/7] = contribution
 	}
+	return matrix
+}
 
-	var raw map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
-		return nil, err
+func GetContributionsFromApi(ctx context.Context, client *github.Client, username string, r Range) (Calendar, error) {
+	variables := r.variables()
+	variables["username"] = username
+	var response contributionsResponse
+	if err := client.Query(ctx, query, variables, &response); err != nil {
+		return Calendar{}, github.UserError(err, username)
 	}
 
-	contributions, err := parseContributions(raw)
+	contributions, err := parseContributions(response, username)
 	if err != nil {
-		return nil, err
+		return Calendar{}, err
 	}
 
-	return MakeContributionMatrix(contributions), nil
+	return Calendar{
+		Matrix:    MakeContributionMatrix(contributions),
+		Total:     response.User.ContributionsCollection.ContributionCalendar.TotalContributions,
+		Breakdown: parseBreakdown(response.User.ContributionsCollection.breakdownResponse),
+		Years:     response.User.ContributionsCollection.ContributionYears,
+	}, nil
 }
@@ -0,0 +1,90 @@
+package contribution
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min, sec int) time.Time {
+	return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+}
+
+func TestParseRange(t *testing.T) {
+	now := date(2024, time.June, 15, 12, 0, 0)
+	tests := []struct {
+		name     string
+		from, to string
+		want     Range
+		err      string
+	}{
+		{name: "default"},
+		{
+			name: "both",
+			from: "2023-01-01", to: "2023-12-31",
+			want: Range{From: date(2023, time.January, 1, 0, 0, 0), To: date(2023, time.December, 31, 23, 59, 59)},
+		},
+		{
+			name: "from only",
+			from: "2022-03-01",
+			want: Range{From: date(2022, time.March, 1, 0, 0, 0), To: date(2023, time.February, 28, 23, 59, 59)},
+		},
+		{
+			name: "to only",
+			to:   "2023-03-01",
+			want: Range{From: date(2022, time.March, 2, 0, 0, 0), To: date(2023, time.March, 1, 23, 59, 59)},
+		},
+		{
+			name: "clamped to today",
+			from: "2024-01-01", to: "2024-12-31",
+			want: Range{From: date(2024, time.January, 1, 0, 0, 0), To: date(2024, time.June, 15, 23, 59, 59)},
+		},
+		{
+			name: "one year from the past clamped to today",
+			from: "2024-02-01",
+			want: Range{From: date(2024, time.February, 1, 0, 0, 0), To: date(2024, time.June, 15, 23, 59, 59)},
+		},
+		{name: "more than a year", from: "2022-01-01", to: "2023-01-01", err: "must not exceed one year"},
+		{name: "from after to", from: "2023-06-02", to: "2023-06-01", err: "ends before it starts"},
+		{name: "in the future", from: "2025-01-01", err: "ends before it starts"},
+		{name: "invalid from", from: "2023-13-01", err: `invalid from date "2023-13-01"`},
+		{name: "invalid to", to: "01/02/2023", err: `invalid to date "01/02/2023"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRange(tt.from, tt.to, now)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.From.Equal(tt.want.From) || !got.To.Equal(tt.want.To) {
+				t.Errorf("ParseRange(%q, %q) = %s, want %s", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYearRange(t *testing.T) {
+	now := date(2024, time.June, 15, 12, 0, 0)
+	tests := []struct {
+		year int
+		want Range
+	}{
+		{2023, Range{From: date(2023, time.January, 1, 0, 0, 0), To: date(2023, time.December, 31, 23, 59, 59)}},
+		{2024, Range{From: date(2024, time.January, 1, 0, 0, 0), To: date(2024, time.June, 15, 23, 59, 59)}},
+	}
+	for _, tt := range tests {
+		got := YearRange(tt.year, now)
+		if !got.From.Equal(tt.want.From) || !got.To.Equal(tt.want.To) {
+			t.Errorf("YearRange(%d) = %s, want %s", tt.year, got, tt.want)
+		}
+		if err := got.validate(); err != nil {
+			t.Errorf("YearRange(%d) is invalid: %v", tt.year, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package contribution
+
+import (
+	"context"
+	"time"
+
+	"github-dashboard/pkg/github"
+)
+
+// ContributionKind is the type of a listed contribution.
+type ContributionKind string
+
+const (
+	KindCommits     ContributionKind = "commits"
+	KindPullRequest ContributionKind = "pr"
+	KindIssue       ContributionKind = "issue"
+	KindReview      ContributionKind = "review"
+)
+
+// dayListSize caps every kind of contributions listed for a day.
+const dayListSize = 25
+
+const dayQuery = `
+query($username: String!, $from: DateTime!, $to: DateTime!, $first: Int!) {` + github.RateLimitSelection + `
+        user(login: $username) {
+            contributionsCollection(from: $from, to: $to) {
+                commitContributionsByRepository(maxRepositories: $first) {
+                    repository {
+                        nameWithOwner
+                        url
+                    }
+                    contributions {
+                        totalCount
+                    }
+                }
+                pullRequestContributions(first: $first) {
+                    nodes {
+                        pullRequest {
+                            number
+                            title
+                            url
+                            repository {
+                                nameWithOwner
+                            }
+                        }
+                    }
+                }
+                issueContributions(first: $first) {
+                    nodes {
+                        issue {
+                            number
+                            title
+                            url
+                            repository {
+                                nameWithOwner
+                            }
+                        }
+                    }
+                }
+                pullRequestReviewContributions(first: $first) {
+                    nodes {
+                        pullRequestReview {
+                            url
+                        }
+                        pullRequest {
+                            number
+                            title
+                            repository {
+                                nameWithOwner
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    }
+`
+
+// DayContribution is a contribution listed for a single day. Commits are
+// grouped by repository.
+type DayContribution struct {
+	Kind       ContributionKind `json:"kind"`
+	Repository string           `json:"repository"`
+	// Number is the pull request or issue number, 0 for commits.
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	// Count is the number of commits, 1 for other kinds.
+	Count int `json:"count"`
+}
+
+type issueNode struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+type dayResponse struct {
+	User *struct {
+		ContributionsCollection struct {
+			CommitContributionsByRepository []struct {
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+					URL           string `json:"url"`
+				} `json:"repository"`
+				Contributions struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"contributions"`
+			} `json:"commitContributionsByRepository"`
+			PullRequestContributions struct {
+				Nodes []struct {
+					PullRequest issueNode `json:"pullRequest"`
+				} `json:"nodes"`
+			} `json:"pullRequestContributions"`
+			IssueContributions struct {
+				Nodes []struct {
+					Issue issueNode `json:"issue"`
+				} `json:"nodes"`
+			} `json:"issueContributions"`
+			PullRequestReviewContributions struct {
+				Nodes []struct {
+					PullRequestReview struct {
+						URL string `json:"url"`
+					} `json:"pullRequestReview"`
+					PullRequest issueNode `json:"pullRequest"`
+				} `json:"nodes"`
+			} `json:"pullRequestReviewContributions"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+// GetDayContributions lists the commits, pull requests, issues and reviews
+// the user contributed on the day of date.
+func GetDayContributions(ctx context.Context, client *github.Client, username string, date time.Time) ([]DayContribution, error) {
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	variables := map[string]interface{}{
+		"username": username,
+		"from":     from.Format(time.RFC3339),
+		"to":       endOfDay(from).Format(time.RFC3339),
+		"first":    dayListSize,
+	}
+	var response dayResponse
+	if err := client.Query(ctx, dayQuery, variables, &response); err != nil {
+		return nil, github.UserError(err, username)
+	}
+	if response.User == nil {
+		return nil, &github.UserNotFoundError{Login: username}
+	}
+
+	collection := response.User.ContributionsCollection
+	var contributions []DayContribution
+	for _, commits := range collection.CommitContributionsByRepository {
+		contributions = append(contributions, DayContribution{
+			Kind:       KindCommits,
+			Repository: commits.Repository.NameWithOwner,
+			URL:        commits.Repository.URL,
+			Count:      commits.Contributions.TotalCount,
+		})
+	}
+	for _, node := range collection.PullRequestContributions.Nodes {
+		contributions = append(contributions, issueContribution(KindPullRequest, node.PullRequest))
+	}
+	for _, node := range collection.IssueContributions.Nodes {
+		contributions = append(contributions, issueContribution(KindIssue, node.Issue))
+	}
+	for _, node := range collection.PullRequestReviewContributions.Nodes {
+		review := issueContribution(KindReview, node.PullRequest)
+		review.URL = node.PullRequestReview.URL
+		contributions = append(contributions, review)
+	}
+	return contributions, nil
+}
+
+func issueContribution(kind ContributionKind, node issueNode) DayContribution {
+	return DayContribution{
+		Kind:       kind,
+		Repository: node.Repository.NameWithOwner,
+		Number:     node.Number,
+		Title:      node.Title,
+		URL:        node.URL,
+		Count:      1,
+	}
+}
@@ -1,13 +1,28 @@
 package contribution
 
 import (
-	"fmt"
 	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
 )
 
+// DayDisplay holds the glyphs of the calendar days.
 type DayDisplay struct {
 	Empty string
 	Full  string
+	// Levels optionally draws every intensity level with its own glyph.
+	Levels []string
+}
+
+func (d DayDisplay) glyph(level int) string {
+	switch {
+	case level < len(d.Levels):
+		return d.Levels[level]
+	case level == 0:
+		return d.Empty
+	}
+	return d.Full
 }
 
 var squareDayDisplay = DayDisplay{
@@ -15,58 +30,52 @@ var squareDayDisplay = DayDisplay{
 	Full:  "■",
 }
 
-const Width = 2*53 + 5
+// Width is the width of a full calendar, a year spans up to 54 partial weeks.
+const Width = 2*54 + weekHeaderWidth
 const Height = 8 * 2
 
-// GitHub contribution colors using RGB values
-var colorPalette = []string{
-	"\x1b[38;2;235;237;240m", // Empty (light gray)
-	"\x1b[38;2;155;233;168m", // Light green
-	"\x1b[38;2;64;196;99m",   // Medium green
-	"\x1b[38;2;47;182;125m",  // Dark green
-	"\x1b[38;2;26;147;111m",  // Darkest green
+// weekMonths returns the month of every week, taken from its first day that
+// isn't padding.
+func weekMonths(matrix [][]ContributionDay) []uint8 {
+	months := make([]uint8, len(matrix[0]))
+	for week := range months {
+		for _, row := range matrix {
+			if week < len(row) && !row[week].IsPadding() {
+				months[week] = row[week].Month
+				break
+			}
+		}
+	}
+	return months
 }
 
-const Reset = "\x1b[0m" // Reset color
-
-func colorDispatcher(count uint64) string {
-	if count == 1 {
-		return colorPalette[1]
-	}
-	if count <= 3 {
-		return colorPalette[2]
+// formatMonthHeader labels the months above weeks rendered cellWidth columns
+// wide, months too narrow for their label are left blank.
+func formatMonthHeader(matrix [][]ContributionDay, cellWidth int) string {
+	months := weekMonths(matrix)
+	if len(months) == 0 {
+		return ""
 	}
-	if count <= 5 {
-		return colorPalette[3]
+
+	output := ""
+	label := func(month uint8, span int, last bool) {
+		width := cellWidth * span
+		if width < 4 && !(last && width >= 3) {
+			output += strings.Repeat(" ", width)
+			return
+		}
+		output += MonthAbreviations[month] + strings.Repeat(" ", width-3)
 	}
-	return colorPalette[4]
-}
 
-func FormatMonthHeader(firstRow []ContributionDay) string {
-	lastMonth := firstRow[0].Month
 	lastMonthPosition := 0
-
-	output := ""
-	for i, contDay := range firstRow {
-		if contDay.Month != lastMonth {
-			span := i - lastMonthPosition
-			lastMonth = contDay.Month
+	for i, month := range months {
+		if month != months[lastMonthPosition] {
+			label(months[lastMonthPosition], i-lastMonthPosition, false)
 			lastMonthPosition = i
-
-			if span < 2 {
-				output += strings.Repeat(" ", 2*span)
-				continue
-			}
-			output += fmt.Sprintf("%s%s", contDay.GetPreviousMonthAbreviation(), strings.Repeat(" ", 2*span-3))
 		}
 	}
-	if len(firstRow)-lastMonthPosition >= 2 {
-		span := len(firstRow) - lastMonthPosition
-		contDay := firstRow[lastMonthPosition]
-		output += fmt.Sprintf("%s%s", contDay.GetMonthAbreviation(), strings.Repeat(" ", 2*span-3))
-	}
-	output = strings.TrimRight(output, " ")
-	return output
+	label(months[lastMonthPosition], len(months)-lastMonthPosition, true)
+	return strings.TrimRight(output, " ")
 }
 
 func formatWeekDays(dayNo int) string {
@@ -82,24 +91,161 @@ func formatWeekDays(dayNo int) string {
 	return strings.Repeat(" ", 5)
 }
 
+// CalendarMode selects how densely the calendar is drawn.
+type CalendarMode int
+
+const (
+	// CalendarFull draws every day as a glyph followed by a space.
+	CalendarFull CalendarMode = iota
+	// CalendarCompact draws every day as a single-width glyph.
+	CalendarCompact
+	// CalendarSparkline draws one bar per week, scaled to the busiest week.
+	CalendarSparkline
+)
+
+const weekHeaderWidth = 5
+
+// minCompactWeeks is the shortest period, roughly a quarter, worth drawing
+// day by day. Narrower calendars fall back to the sparkline.
+const minCompactWeeks = 13
+
+var sparklineBars = []rune("▁▂▃▄▅▆▇█")
+
+// CalendarOptions tune how the calendar is rendered.
+type CalendarOptions struct {
+	Scale ColorScale
+	// Theme and Glyphs default to DefaultTheme and squares.
+	Theme  Theme
+	Glyphs DayDisplay
+	// Selected is highlighted, or its week in the sparkline. Narrow calendars
+	// scroll back to keep it visible.
+	Selected time.Time
+}
+
+// calendarRenderer renders calendars with the levels of the whole matrix, so
+// that trimmed calendars keep their colors.
+type calendarRenderer struct {
+	CalendarOptions
+	level func(ContributionDay) int
+}
+
+func newCalendarRenderer(matrix [][]ContributionDay, options CalendarOptions) calendarRenderer {
+	options = options.withDefaults()
+	return calendarRenderer{CalendarOptions: options, level: options.Scale.dayLevels(matrix)}
+}
+
+func (o CalendarOptions) withDefaults() CalendarOptions {
+	if o.Theme.Name == "" {
+		o.Theme = DefaultTheme
+	}
+	if o.Glyphs.Empty == "" && o.Glyphs.Full == "" {
+		o.Glyphs = squareDayDisplay
+	}
+	return o
+}
+
+// style colors the level, the selected day is shown in reverse video. The
+// colors are kept in full, the writer degrades them to the terminal.
+func (o CalendarOptions) style(level int, selected bool) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if color := o.Theme.color(level); color != nil {
+		style = style.Foreground(color)
+	}
+	return style.Reverse(selected)
+}
+
+// cell renders the glyph of the level in its color.
+func (o CalendarOptions) cell(level int, selected bool) string {
+	return o.style(level, selected).Render(o.Glyphs.glyph(level))
+}
+
+// FormatCalendar renders the calendar in the full theme colors, print it
+// through a color aware writer such as lipgloss.Println to degrade them.
 func FormatCalendar(matrix [][]ContributionDay, leftPadding uint, withWeekHeader bool) string {
+	return newCalendarRenderer(matrix, CalendarOptions{}).calendar(matrix, leftPadding, withWeekHeader, 2)
+}
+
+func (r calendarRenderer) mode(matrix [][]ContributionDay, mode CalendarMode, withWeekHeader bool) string {
+	switch mode {
+	case CalendarCompact:
+		return r.calendar(matrix, 0, withWeekHeader, 1)
+	case CalendarSparkline:
+		return r.sparkline(matrix, withWeekHeader)
+	}
+	return r.calendar(matrix, 0, withWeekHeader, 2)
+}
+
+// FitCalendar renders as many of the latest weeks as width allows, switching
+// to single-width cells and then to a weekly sparkline as the width shrinks.
+func FitCalendar(matrix [][]ContributionDay, width int, options CalendarOptions) string {
+	r := newCalendarRenderer(matrix, options)
+	weeks := len(matrix[0])
+	switch {
+	case width >= weekHeaderWidth+2*weeks-1:
+		return r.mode(matrix, CalendarFull, true)
+	case width >= weekHeaderWidth+minCompactWeeks:
+		return r.mode(weeksUntil(matrix, width-weekHeaderWidth, options.Selected), CalendarCompact, true)
+	case width >= weekHeaderWidth+minCompactWeeks/2:
+		return r.mode(weeksUntil(matrix, width-weekHeaderWidth, options.Selected), CalendarSparkline, true)
+	}
+	return r.mode(weeksUntil(matrix, width, options.Selected), CalendarSparkline, false)
+}
+
+// LastWeeks keeps the latest n weeks of the calendar.
+func LastWeeks(matrix [][]ContributionDay, n int) [][]ContributionDay {
+	trimmed := make([][]ContributionDay, len(matrix))
+	for i, row := range matrix {
+		trimmed[i] = row[max(len(row)-max(n, 0), 0):]
+	}
+	return trimmed
+}
+
+// weeksUntil keeps n weeks of the calendar, the latest ones unless the week
+// of the selected day lies before them.
+func weeksUntil(matrix [][]ContributionDay, n int, selected time.Time) [][]ContributionDay {
+	week := selectedWeek(matrix, selected)
+	if week < 0 || week >= len(matrix[0])-n {
+		return LastWeeks(matrix, n)
+	}
+	trimmed := make([][]ContributionDay, len(matrix))
+	for i, row := range matrix {
+		trimmed[i] = row[week:min(week+max(n, 0), len(row))]
+	}
+	return trimmed
+}
+
+// selectedWeek returns the week of the selected day, -1 when it isn't shown.
+func selectedWeek(matrix [][]ContributionDay, selected time.Time) int {
+	if selected.IsZero() {
+		return -1
+	}
+	for _, row := range matrix {
+		for week, day := range row {
+			if !day.IsPadding() && day.Date.Equal(selected) {
+				return week
+			}
+		}
+	}
+	return -1
+}
+
+func (r calendarRenderer) calendar(matrix [][]ContributionDay, leftPadding uint, withWeekHeader bool, cellWidth int) string {
 	padding := strings.Repeat(" ", int(leftPadding))
-	calendar := FormatMonthHeader(matrix[0]) + "\n"
+	calendar := formatMonthHeader(matrix, cellWidth) + "\n"
 	if withWeekHeader {
 		calendar = padding + formatWeekDays(0) + calendar
 	}
+	gap := strings.Repeat(" ", cellWidth-1)
 
 	for dayNo, row := range matrix {
 		rowStr := ""
 		for _, day := range row {
-			color := colorDispatcher(day.ContributionCount)
-
-			if day.ContributionCount == 0 {
-				rowStr += squareDayDisplay.Empty
+			if day.IsPadding() {
+				rowStr += " "
 			} else {
-				rowStr += fmt.Sprintf("%s%s%s", color, squareDayDisplay.Full, Reset)
+				rowStr += r.cell(r.level(day), !r.Selected.IsZero() && day.Date.Equal(r.Selected))
 			}
-			rowStr += " "
+			rowStr += gap
 		}
 		if withWeekHeader {
 			rowStr = formatWeekDays(dayNo) + rowStr
@@ -108,3 +254,40 @@ func FormatCalendar(matrix [][]ContributionDay, leftPadding uint, withWeekHeader
 	}
 	return strings.TrimRight(calendar, "\n")
 }
+
+// sparkline renders the weekly totals as a single line of bars.
+func (r calendarRenderer) sparkline(matrix [][]ContributionDay, withWeekHeader bool) string {
+	totals := make([]uint64, len(matrix[0]))
+	var busiest uint64
+	for _, row := range matrix {
+		for week, day := range row {
+			totals[week] += day.ContributionCount
+			busiest = max(busiest, totals[week])
+		}
+	}
+	level := r.Scale.countLevels(totals)
+
+	week := selectedWeek(matrix, r.Selected)
+	line := ""
+	for i, total := range totals {
+		switch {
+		case i == week:
+			bar := ' '
+			if total > 0 {
+				bar = sparklineBars[int((total-1)*uint64(len(sparklineBars))/busiest)]
+			}
+			line += r.style(0, true).Render(string(bar))
+		case total == 0:
+			line += " "
+		default:
+			bar := sparklineBars[int((total-1)*uint64(len(sparklineBars))/busiest)]
+			line += r.style(level(total), false).Render(string(bar))
+		}
+	}
+
+	header := formatMonthHeader(matrix, 1)
+	if withWeekHeader {
+		return strings.Repeat(" ", weekHeaderWidth) + header + "\n" + "Week " + line
+	}
+	return header + "\n" + line
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const DefaultCacheTTL = 15 * time.Minute
+
+var ErrCacheMiss = errors.New("no cached response available")
+
+// CachePolicy controls how Client.Query uses the response cache.
+type CachePolicy int
+
+const (
+	// CacheDefault serves responses younger than the cache TTL and fetches the rest.
+	CacheDefault CachePolicy = iota
+	// CacheOnly serves cached responses of any age and never hits the network.
+	CacheOnly
+	// CacheRefresh always asks the API, revalidating the cached ETag when there is one.
+	CacheRefresh
+)
+
+// Cache stores GraphQL responses on disk keyed by endpoint, token, query and variables.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// DefaultCacheDir returns github-dashboard directory inside the user cache
+// directory, $XDG_CACHE_HOME or ~/.cache on Linux.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "github-dashboard"), nil
+}
+
+type cacheEntry struct {
+	ETag      string          `json:"etag,omitempty"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func (e *cacheEntry) isFresh(ttl time.Duration) bool {
+	return time.Since(e.FetchedAt) < ttl
+}
+
+// key identifies a response by endpoint, token and request body. Responses of
+// one token are never served to another, which may see different data.
+func (c *Cache) key(endpoint, token string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(endpoint))
+	hash.Write([]byte{0})
+	tokenHash := sha256.Sum256([]byte(token))
+	hash.Write(tokenHash[:])
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *Cache) load(key string) (*cacheEntry, error) {
+	content, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return nil, ErrCacheMiss
+	}
+	return &entry, nil
+}
+
+func (c *Cache) store(key string, entry *cacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a concurrent reader never sees a partial entry.
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
@@ -0,0 +1,322 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultEndpoint = "https://api.github.com/graphql"
+
+const (
+	DefaultMaxRetries       = 3
+	DefaultMaxRateLimitWait = 2 * time.Minute
+)
+
+// Client sends GraphQL queries to the GitHub API.
+type Client struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+	rateLimit  *rateLimitTracker
+	cache      *Cache
+	policy     CachePolicy
+	// fetchedAt, when set, records when the oldest served response was fetched.
+	fetchedAt  *time.Time
+	maxRetries int
+	// maxRateLimitWait bounds how long a rate limited request is allowed to
+	// wait before it's retried, longer waits return RateLimitError instead.
+	maxRateLimitWait time.Duration
+}
+
+type ClientOption func(*Client)
+
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithMaxRetries(retries int) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = retries
+	}
+}
+
+func WithMaxRateLimitWait(wait time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRateLimitWait = wait
+	}
+}
+
+func WithCache(cache *Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		endpoint:         DefaultEndpoint,
+		token:            token,
+		httpClient:       http.DefaultClient,
+		rateLimit:        &rateLimitTracker{},
+		maxRetries:       DefaultMaxRetries,
+		maxRateLimitWait: DefaultMaxRateLimitWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// WithCachePolicy returns a copy of the client using policy. The copy shares
+// the cache and the rate limit state with the original.
+func (c *Client) WithCachePolicy(policy CachePolicy) *Client {
+	clone := *c
+	clone.policy = policy
+	return &clone
+}
+
+// WithFetchTime returns a copy of the client recording in fetchedAt when the
+// oldest response it served was fetched from the API. Cached responses keep
+// the time they were stored at. The copy must not run concurrent queries.
+func (c *Client) WithFetchTime(fetchedAt *time.Time) *Client {
+	clone := *c
+	clone.fetchedAt = fetchedAt
+	return &clone
+}
+
+func (c *Client) recordFetchTime(t time.Time) {
+	if c.fetchedAt != nil && (c.fetchedAt.IsZero() || t.Before(*c.fetchedAt)) {
+		*c.fetchedAt = t
+	}
+}
+
+// RateLimit returns the API quota reported by the most recent response.
+func (c *Client) RateLimit() RateLimit {
+	return c.rateLimit.get()
+}
+
+// WebURL builds a link to the web interface of the host the client talks to.
+func (c *Client) WebURL(path ...string) string {
+	return strings.Join(append([]string{webURL(c.endpoint)}, path...), "/")
+}
+
+type GraphQLError struct {
+	Message string        `json:"message"`
+	Type    string        `json:"type"`
+	Path    []interface{} `json:"path"`
+}
+
+func (e GraphQLError) Error() string {
+	if e.Type == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+
+	etag        string
+	notModified bool
+}
+
+// Query executes a GraphQL query and decodes the response `data` field into data.
+// GraphQL level errors are returned as GraphQLErrors. Rate limited requests are
+// retried when the limit is lifted soon enough, otherwise RateLimitError is returned.
+// Responses are served from and stored in the cache according to the cache policy.
+func (c *Client) Query(ctx context.Context, query string, variables map[string]interface{}, data interface{}) error {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	if c.cache == nil {
+		if c.policy == CacheOnly {
+			return ErrCacheMiss
+		}
+		return c.fetch(ctx, body, "", func(*graphQLResponse) {
+			c.recordFetchTime(time.Now())
+		}, data)
+	}
+
+	key := c.cache.key(c.endpoint, c.token, body)
+	entry, err := c.cache.load(key)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		log.Printf("[API] Failed to read cache entry %s: %v", key, err)
+	}
+	switch {
+	case c.policy == CacheOnly && entry == nil:
+		return ErrCacheMiss
+	case c.policy == CacheOnly, c.policy == CacheDefault && entry != nil && entry.isFresh(c.cache.ttl):
+		c.recordFetchTime(entry.FetchedAt)
+		return unmarshalData(entry.Data, data)
+	}
+
+	var etag string
+	if entry != nil {
+		etag = entry.ETag
+	}
+	return c.fetch(ctx, body, etag, func(response *graphQLResponse) {
+		if response.notModified {
+			response.Data = entry.Data
+			response.etag = entry.ETag
+		}
+		if len(response.Errors) > 0 {
+			return
+		}
+		fetchedAt := time.Now()
+		c.recordFetchTime(fetchedAt)
+		err := c.cache.store(key, &cacheEntry{ETag: response.etag, FetchedAt: fetchedAt, Data: response.Data})
+		if err != nil {
+			log.Printf("[API] Failed to write cache entry %s: %v", key, err)
+		}
+	}, data)
+}
+
+// fetch sends the request, retrying it while rate limited. onResponse, when
+// set, sees the response before it's decoded.
+func (c *Client) fetch(ctx context.Context, body []byte, etag string, onResponse func(*graphQLResponse), data interface{}) error {
+	for attempt := 0; ; attempt++ {
+		response, retryAfter, err := c.post(ctx, body, etag, attempt)
+		if retryAfter > 0 {
+			log.Printf("[API] Rate limited, retrying in %s", retryAfter)
+			if err := sleep(ctx, retryAfter); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if onResponse != nil {
+			onResponse(response)
+		}
+		return c.decode(response, data)
+	}
+}
+
+// post sends a single request. A positive duration means the request was rate
+// limited and should be retried after that time.
+func (c *Client) post(ctx context.Context, body []byte, etag string, attempt int) (*graphQLResponse, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, &NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+	c.rateLimit.updateFromHeaders(resp.Header)
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &graphQLResponse{notModified: true}, 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		wait, rateLimitErr := rateLimitWait(resp, bodyBytes, attempt)
+		if rateLimitErr != nil {
+			if wait > 0 && attempt < c.maxRetries && wait <= c.maxRateLimitWait {
+				return nil, wait, nil
+			}
+			return nil, 0, rateLimitErr
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, 0, &AuthError{Message: errorMessage(bodyBytes)}
+		}
+		return nil, 0, &HTTPError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, 0, &NetworkError{Err: err}
+	}
+	response.etag = resp.Header.Get("ETag")
+	return &response, 0, nil
+}
+
+// errorMessage extracts `message` from a JSON error body, falling back to the raw body.
+func errorMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+func (c *Client) decode(response *graphQLResponse, data interface{}) error {
+	// A revalidated cache entry carries the quota from the time it was stored.
+	if !response.notModified && len(response.Data) > 0 && string(response.Data) != "null" {
+		var field rateLimitField
+		if err := json.Unmarshal(response.Data, &field); err == nil {
+			c.rateLimit.updateFromField(field)
+		}
+	}
+
+	if len(response.Errors) > 0 {
+		for _, e := range response.Errors {
+			switch e.Type {
+			case "RATE_LIMITED":
+				return &RateLimitError{ResetAt: c.RateLimit().ResetAt}
+			case "INSUFFICIENT_SCOPES":
+				return newScopeError(e)
+			}
+		}
+		return response.Errors
+	}
+	return unmarshalData(response.Data, data)
+}
+
+func unmarshalData(raw json.RawMessage, data interface{}) error {
+	if data == nil || len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, data)
+}
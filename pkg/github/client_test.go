@@ -0,0 +1,405 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type viewerData struct {
+	Viewer struct {
+		Login string `json:"login"`
+	} `json:"viewer"`
+}
+
+const viewerQuery = `query { viewer { login } }`
+
+// newTestClient serves every request with handler and counts the requests.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) (*Client, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	opts = append([]ClientOption{WithEndpoint(server.URL), WithHTTPClient(server.Client())}, opts...)
+	return NewClient("token", opts...), &requests
+}
+
+func TestQuery(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if request.Query != viewerQuery || request.Variables["first"] != float64(10) {
+			t.Errorf("request = %+v", request)
+		}
+		w.Write([]byte(`{"data": {"rateLimit": {"cost": 1, "limit": 5000, "remaining": 4999, "resetAt": "2030-01-01T00:00:00Z"}, "viewer": {"login": "octocat"}}}`))
+	})
+
+	var data viewerData
+	if err := client.Query(context.Background(), viewerQuery, map[string]interface{}{"first": 10}, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Viewer.Login != "octocat" {
+		t.Errorf("login = %q, want octocat", data.Viewer.Login)
+	}
+	if rl := client.RateLimit(); rl.Limit != 5000 || rl.Remaining != 4999 || rl.Cost != 1 {
+		t.Errorf("rate limit = %+v", rl)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{
+			name:   "graphql",
+			status: http.StatusOK,
+			body:   `{"data": null, "errors": [{"message": "Could not resolve to a User", "type": "NOT_FOUND"}]}`,
+			check: func(err error) bool {
+				var gqlErrors GraphQLErrors
+				return errors.As(err, &gqlErrors) && gqlErrors.hasType("NOT_FOUND")
+			},
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusOK,
+			body:   `{"data": null, "errors": [{"message": "API rate limit exceeded", "type": "RATE_LIMITED"}]}`,
+			check: func(err error) bool {
+				var rateLimitErr *RateLimitError
+				return errors.As(err, &rateLimitErr)
+			},
+		},
+		{
+			name:   "scopes",
+			status: http.StatusOK,
+			body:   `{"errors": [{"message": "requires one of the following scopes: ['read:org'], but your token has only been granted the: ['repo'] scopes.", "type": "INSUFFICIENT_SCOPES"}]}`,
+			check: func(err error) bool {
+				var scopeErr *ScopeError
+				return errors.As(err, &scopeErr) && scopeErr.Required[0] == "read:org" && scopeErr.Granted[0] == "repo"
+			},
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			body:   `{"message": "Bad credentials"}`,
+			check: func(err error) bool {
+				var authErr *AuthError
+				return errors.As(err, &authErr) && authErr.Message == "Bad credentials"
+			},
+		},
+		{
+			name:   "server error",
+			status: http.StatusBadGateway,
+			body:   `bad gateway`,
+			check: func(err error) bool {
+				var httpErr *HTTPError
+				return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusBadGateway
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			err := client.Query(context.Background(), viewerQuery, nil, &viewerData{})
+			if err == nil || !tt.check(err) {
+				t.Errorf("unexpected error %T: %v", err, err)
+			}
+		})
+	}
+}
+
+func TestQueryRevalidatesCache(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"data": {"viewer": {"login": "octocat"}}}`))
+	}, WithCache(NewCache(t.TempDir(), DefaultCacheTTL)))
+
+	var data viewerData
+	if err := client.Query(context.Background(), viewerQuery, nil, &data); err != nil {
+		t.Fatal(err)
+	}
+	// A fresh entry is served without a request.
+	if err := client.Query(context.Background(), viewerQuery, nil, &data); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	data = viewerData{}
+	if err := client.WithCachePolicy(CacheRefresh).Query(context.Background(), viewerQuery, nil, &data); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if data.Viewer.Login != "octocat" {
+		t.Errorf("revalidated login = %q, want octocat", data.Viewer.Login)
+	}
+}
+
+func TestQueryCacheOnlyMiss(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}, WithCache(NewCache(t.TempDir(), DefaultCacheTTL)))
+
+	err := client.WithCachePolicy(CacheOnly).Query(context.Background(), viewerQuery, nil, &viewerData{})
+	if !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("err = %v, want ErrCacheMiss", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("requests = %d, want 0", requests.Load())
+	}
+}
+
+func TestQueryRetriesRateLimited(t *testing.T) {
+	limited := true
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if limited {
+			limited = false
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "You have exceeded a secondary rate limit"}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"viewer": {"login": "octocat"}}}`))
+	})
+
+	var data viewerData
+	if err := client.Query(context.Background(), viewerQuery, nil, &data); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if data.Viewer.Login != "octocat" {
+		t.Errorf("login = %q, want octocat", data.Viewer.Login)
+	}
+}
+
+func TestQueryRateLimitWaitTooLong(t *testing.T) {
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	err := client.Query(context.Background(), viewerQuery, nil, &viewerData{})
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || !rateLimitErr.Secondary {
+		t.Errorf("err = %v, want a secondary RateLimitError", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestQueryExhaustedQuota(t *testing.T) {
+	tests := []struct {
+		name  string
+		reset string
+	}{
+		{"unknown reset", ""},
+		{"past reset", "1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Limit", "5000")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				if tt.reset != "" {
+					w.Header().Set("X-RateLimit-Reset", tt.reset)
+				}
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			})
+
+			err := client.Query(context.Background(), viewerQuery, nil, &viewerData{})
+			var rateLimitErr *RateLimitError
+			if !errors.As(err, &rateLimitErr) || rateLimitErr.Secondary {
+				t.Errorf("err = %v, want a primary RateLimitError", err)
+			}
+			if got := requests.Load(); got != 1 {
+				t.Errorf("requests = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestCacheIsPerToken(t *testing.T) {
+	cache := NewCache(t.TempDir(), DefaultCacheTTL)
+	client, requests := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"viewer": {"login": "` + r.Header.Get("Authorization") + `"}}}`))
+	}, WithCache(cache))
+	other := NewClient("other", WithEndpoint(client.Endpoint()), WithCache(cache))
+
+	var data viewerData
+	if err := client.Query(context.Background(), viewerQuery, nil, &data); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Query(context.Background(), viewerQuery, nil, &data); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if data.Viewer.Login != "Bearer other" {
+		t.Errorf("login = %q, want the response of the other token", data.Viewer.Login)
+	}
+}
+
+func TestQueryFetchTime(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"viewer": {"login": "octocat"}}}`))
+	}, WithCache(NewCache(t.TempDir(), DefaultCacheTTL)))
+
+	var fetched time.Time
+	if err := client.WithFetchTime(&fetched).Query(context.Background(), viewerQuery, nil, &viewerData{}); err != nil {
+		t.Fatal(err)
+	}
+	if fetched.IsZero() {
+		t.Fatal("fetch time of a network response not recorded")
+	}
+
+	// A fresh cached response keeps the time it was fetched at.
+	time.Sleep(10 * time.Millisecond)
+	var cached time.Time
+	if err := client.WithFetchTime(&cached).Query(context.Background(), viewerQuery, nil, &viewerData{}); err != nil {
+		t.Fatal(err)
+	}
+	if !cached.Equal(fetched) {
+		t.Errorf("cached fetch time = %s, want %s", cached, fetched)
+	}
+}
+
+// repositoriesHandler serves total repositories in pages, the cursor is the
+// offset of the next page. With missingUser, pages after the first leave out
+// the user.
+func repositoriesHandler(t *testing.T, total int, missingUser bool, pageSizes *[]int) http.HandlerFunc {
+	omitted := false
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		offset := 0
+		if after, ok := request.Variables["after"].(string); ok {
+			offset, _ = strconv.Atoi(after)
+		}
+		if offset > 0 && missingUser {
+			// Later requests get a null user so that a stale user doesn't page forever.
+			if omitted {
+				w.Write([]byte(`{"data": {"user": null}}`))
+				return
+			}
+			omitted = true
+			w.Write([]byte(`{"data": {}}`))
+			return
+		}
+		first := int(request.Variables["first"].(float64))
+		*pageSizes = append(*pageSizes, first)
+
+		var nodes []map[string]interface{}
+		for i := offset; i < min(offset+first, total); i++ {
+			nodes = append(nodes, map[string]interface{}{
+				"owner":    map[string]string{"login": "octocat"},
+				"name":     fmt.Sprintf("repo-%03d", i),
+				"pushedAt": time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Hour),
+			})
+		}
+		end := offset + len(nodes)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"user": map[string]interface{}{
+					"repositories": map[string]interface{}{
+						"totalCount": total,
+						"pageInfo":   map[string]interface{}{"hasNextPage": end < total, "endCursor": strconv.Itoa(end)},
+						"nodes":      nodes,
+					},
+				},
+			},
+		})
+	}
+}
+
+func TestGetRepositoriesPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		want      int
+		pageSizes []int
+	}{
+		{"all", 0, 150, []int{100, 100}},
+		{"limited to the first page", 40, 40, []int{40}},
+		{"limited on the second page", 120, 120, []int{100, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pageSizes []int
+			client, _ := newTestClient(t, repositoriesHandler(t, 150, false, &pageSizes))
+			list, err := GetRepositories(context.Background(), client, "octocat", tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list.Repositories) != tt.want || list.TotalCount != 150 {
+				t.Errorf("got %d of %d repositories, want %d of 150", len(list.Repositories), list.TotalCount, tt.want)
+			}
+			if list.IsComplete() != (tt.want == 150) {
+				t.Errorf("IsComplete() = %v", list.IsComplete())
+			}
+			if !slices.Equal(pageSizes, tt.pageSizes) {
+				t.Errorf("page sizes = %v, want %v", pageSizes, tt.pageSizes)
+			}
+			// Every repository is fetched once.
+			seen := map[string]bool{}
+			for _, repo := range list.Repositories {
+				if seen[repo.FullName()] {
+					t.Errorf("%s fetched twice", repo.FullName())
+				}
+				seen[repo.FullName()] = true
+			}
+		})
+	}
+}
+
+func TestGetRepositoriesUserGone(t *testing.T) {
+	var pageSizes []int
+	client, requests := newTestClient(t, repositoriesHandler(t, 150, true, &pageSizes))
+
+	// The user of the first page must not be taken for the user of the second one.
+	_, err := GetRepositories(context.Background(), client, "octocat", 0)
+	var notFound *UserNotFoundError
+	if !errors.As(err, &notFound) || notFound.Login != "octocat" {
+		t.Errorf("err = %v, want UserNotFoundError", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
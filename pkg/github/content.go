@@ -0,0 +1,168 @@
+package github
+
+import (
+	"cmp"
+	"context"
+	"path"
+	"slices"
+	"strings"
+)
+
+// TreeEntry is a file or a directory of a git tree.
+type TreeEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Type is "blob", "tree" or "commit" for submodules.
+	Type string `json:"type"`
+}
+
+func (e TreeEntry) IsDir() bool {
+	return e.Type == "tree"
+}
+
+// readmeNames lists README names by preference, matched case-insensitively.
+var readmeNames = []string{"readme.md", "readme.markdown", "readme.rst", "readme.txt", "readme"}
+
+// expression builds a git object expression such as "main:docs/setup.md".
+// An empty branch falls back to HEAD.
+func expression(branch, filePath string) string {
+	if branch == "" {
+		branch = "HEAD"
+	}
+	return branch + ":" + strings.TrimPrefix(filePath, "/")
+}
+
+// GetTree lists the entries of the directory dirPath, "" is the repository root.
+func GetTree(ctx context.Context, client *Client, owner, name, branch, dirPath string) ([]TreeEntry, error) {
+	const query = `
+    query($owner: String!, $name: String!, $expression: String!) {` + RateLimitSelection + `
+        repository(owner: $owner, name: $name) {
+            object(expression: $expression) {
+                ... on Tree {
+                    entries {
+                        name
+                        path
+                        type
+                    }
+                }
+            }
+        }
+    }
+    `
+
+	var response struct {
+		Repository *struct {
+			Object *struct {
+				Entries []TreeEntry `json:"entries"`
+			} `json:"object"`
+		} `json:"repository"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":      owner,
+		"name":       name,
+		"expression": expression(branch, dirPath),
+	}
+	if err := client.Query(ctx, query, variables, &response); err != nil {
+		return nil, err
+	}
+	if response.Repository == nil || response.Repository.Object == nil {
+		return nil, nil
+	}
+	return response.Repository.Object.Entries, nil
+}
+
+// GetFile returns the text of the file at filePath. Empty text is returned
+// for missing and binary files.
+func GetFile(ctx context.Context, client *Client, owner, name, branch, filePath string) (string, error) {
+	const query = `
+    query($owner: String!, $name: String!, $expression: String!) {` + RateLimitSelection + `
+        repository(owner: $owner, name: $name) {
+            object(expression: $expression) {
+                ... on Blob {
+                    isBinary
+                    text
+                }
+            }
+        }
+    }
+    `
+
+	var response struct {
+		Repository *struct {
+			Object *struct {
+				IsBinary bool   `json:"isBinary"`
+				Text     string `json:"text"`
+			} `json:"object"`
+		} `json:"repository"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":      owner,
+		"name":       name,
+		"expression": expression(branch, filePath),
+	}
+	if err := client.Query(ctx, query, variables, &response); err != nil {
+		return "", err
+	}
+	if response.Repository == nil || response.Repository.Object == nil || response.Repository.Object.IsBinary {
+		return "", nil
+	}
+	return response.Repository.Object.Text, nil
+}
+
+// Readme is the README of a repository, Path is empty when there is none.
+type Readme struct {
+	Path string
+	Text string
+}
+
+// GetReadme finds the README in the root of the branch, ignoring the case of
+// its name, and fetches it.
+func GetReadme(ctx context.Context, client *Client, owner, name, branch string) (Readme, error) {
+	entries, err := GetTree(ctx, client, owner, name, branch, "")
+	if err != nil {
+		return Readme{}, err
+	}
+
+	readmePath := findReadme(entries)
+	if readmePath == "" {
+		return Readme{}, nil
+	}
+	text, err := GetFile(ctx, client, owner, name, branch, readmePath)
+	if err != nil {
+		return Readme{}, err
+	}
+	return Readme{Path: readmePath, Text: text}, nil
+}
+
+func findReadme(entries []TreeEntry) string {
+	best := ""
+	bestRank := len(readmeNames) + 1
+	for _, entry := range entries {
+		if entry.Type != "blob" {
+			continue
+		}
+		lower := strings.ToLower(entry.Name)
+		rank := slices.Index(readmeNames, lower)
+		if rank < 0 {
+			if !strings.HasPrefix(lower, "readme.") {
+				continue
+			}
+			rank = len(readmeNames)
+		}
+		if rank < bestRank {
+			best, bestRank = cmp.Or(entry.Path, entry.Name), rank
+		}
+	}
+	return best
+}
+
+// IsMarkdown reports whether the file at filePath is rendered as markdown.
+func IsMarkdown(filePath string) bool {
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type UserNotFoundError struct {
+	Login string
+}
+
+func (e *UserNotFoundError) Error() string {
+	return fmt.Sprintf("user %q not found", e.Login)
+}
+
+// AuthError is returned when the token is missing, expired or revoked.
+type AuthError struct {
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	return "authentication failed: " + e.Message
+}
+
+// ScopeError is returned when the token lacks OAuth scopes required by a query.
+type ScopeError struct {
+	Required []string
+	Granted  []string
+	Message  string
+}
+
+func (e *ScopeError) Error() string {
+	if len(e.Required) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("token lacks %s scope", strings.Join(e.Required, " or "))
+}
+
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return "network error: " + e.Err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected status code %d body: %s", e.StatusCode, e.Body)
+}
+
+func (e GraphQLErrors) hasType(errorType string) bool {
+	for _, err := range e {
+		if err.Type == errorType {
+			return true
+		}
+	}
+	return false
+}
+
+var scopeListPattern = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// newScopeError parses messages like "... requires one of the following
+// scopes: ['read:user'], but your token has only been granted the: ['repo'] scopes."
+func newScopeError(err GraphQLError) *ScopeError {
+	scopeErr := &ScopeError{Message: err.Message}
+	lists := scopeListPattern.FindAllStringSubmatch(err.Message, 2)
+	if len(lists) > 0 {
+		scopeErr.Required = parseScopeList(lists[0][1])
+	}
+	if len(lists) > 1 {
+		scopeErr.Granted = parseScopeList(lists[1][1])
+	}
+	return scopeErr
+}
+
+func parseScopeList(list string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(list, ",") {
+		scope = strings.Trim(strings.TrimSpace(scope), `'"`)
+		if scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// UserError turns a NOT_FOUND error of a `user(login:)` query into UserNotFoundError.
+func UserError(err error, login string) error {
+	var gqlErrors GraphQLErrors
+	if errors.As(err, &gqlErrors) && gqlErrors.hasType("NOT_FOUND") {
+		return &UserNotFoundError{Login: login}
+	}
+	return err
+}
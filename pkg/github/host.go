@@ -0,0 +1,53 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const DefaultAPIURL = "https://api.github.com"
+
+const publicAPIHost = "api.github.com"
+
+// GraphQLEndpoint derives the GraphQL endpoint from an API base URL.
+// Both https://api.github.com and GitHub Enterprise Server URLs such as
+// https://ghe.example.com, https://ghe.example.com/api or
+// https://ghe.example.com/api/v3 are accepted. URLs ending in /graphql are
+// taken as the endpoint itself.
+func GraphQLEndpoint(apiURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(apiURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid API URL %q: %w", apiURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid API URL %q: expected scheme and host", apiURL)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(path, "/graphql") {
+		path = strings.TrimSuffix(path, "/v3")
+		if u.Host != publicAPIHost && !strings.HasSuffix(path, "/api") {
+			path += "/api"
+		}
+		path += "/graphql"
+	}
+	u.Path = path
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// webURL returns the web root matching a GraphQL endpoint, e.g.
+// https://api.github.com/graphql -> https://github.com
+func webURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "https://github.com"
+	}
+	host := u.Host
+	if host == publicAPIHost {
+		host = "github.com"
+	}
+	return u.Scheme + "://" + host
+}
@@ -0,0 +1,54 @@
+package github
+
+import "testing"
+
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := []struct {
+		apiURL string
+		want   string
+	}{
+		{"https://api.github.com", "https://api.github.com/graphql"},
+		{"https://api.github.com/", "https://api.github.com/graphql"},
+		{"https://api.github.com/graphql", "https://api.github.com/graphql"},
+		{" https://api.github.com?x=1#top ", "https://api.github.com/graphql"},
+		{"https://ghe.example.com", "https://ghe.example.com/api/graphql"},
+		{"https://ghe.example.com/api", "https://ghe.example.com/api/graphql"},
+		{"https://ghe.example.com/api/", "https://ghe.example.com/api/graphql"},
+		{"https://ghe.example.com/api/v3", "https://ghe.example.com/api/graphql"},
+		{"https://ghe.example.com/api/graphql", "https://ghe.example.com/api/graphql"},
+		{"http://localhost:8080/graphql", "http://localhost:8080/graphql"},
+		{"http://localhost:8080/proxy/graphql/", "http://localhost:8080/proxy/graphql"},
+		{"http://localhost:8080", "http://localhost:8080/api/graphql"},
+	}
+	for _, tt := range tests {
+		got, err := GraphQLEndpoint(tt.apiURL)
+		if err != nil || got != tt.want {
+			t.Errorf("GraphQLEndpoint(%q) = %q, %v, want %q", tt.apiURL, got, err, tt.want)
+		}
+	}
+}
+
+func TestGraphQLEndpointInvalid(t *testing.T) {
+	for _, apiURL := range []string{"", "api.github.com", "https://", "://ghe.example.com", "http://[::1"} {
+		if got, err := GraphQLEndpoint(apiURL); err == nil {
+			t.Errorf("GraphQLEndpoint(%q) = %q, want an error", apiURL, got)
+		}
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"https://api.github.com/graphql", "https://github.com"},
+		{"https://ghe.example.com/api/graphql", "https://ghe.example.com"},
+		{"http://localhost:8080/graphql", "http://localhost:8080"},
+		{"", "https://github.com"},
+	}
+	for _, tt := range tests {
+		if got := webURL(tt.endpoint); got != tt.want {
+			t.Errorf("webURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the last known state of the API quota.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Cost of the most recent GraphQL query.
+	Cost    int
+	ResetAt time.Time
+}
+
+func (r RateLimit) IsKnown() bool {
+	return r.Limit > 0
+}
+
+type RateLimitError struct {
+	ResetAt time.Time
+	// Secondary is set for abuse-detection limits which are lifted after Retry-After.
+	Secondary bool
+}
+
+func (e *RateLimitError) Error() string {
+	kind := "API rate limit"
+	if e.Secondary {
+		kind = "secondary API rate limit"
+	}
+	if e.ResetAt.IsZero() {
+		return kind + " exceeded"
+	}
+	return fmt.Sprintf("%s exceeded, resets at %s", kind, e.ResetAt.Local().Format("15:04:05"))
+}
+
+type rateLimitTracker struct {
+	mu      sync.Mutex
+	current RateLimit
+}
+
+func (t *rateLimitTracker) get() RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+func (t *rateLimitTracker) updateFromHeaders(header http.Header) {
+	limit, errLimit := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if errLimit != nil || errRemaining != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current.Limit = limit
+	t.current.Remaining = remaining
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		t.current.ResetAt = time.Unix(reset, 0)
+	}
+}
+
+// RateLimitSelection requests the `rateLimit` object, queries select it next
+// to their own data so the client keeps track of the quota.
+const RateLimitSelection = `
+        rateLimit {
+            cost
+            limit
+            remaining
+            resetAt
+        }`
+
+// rateLimitField is the GraphQL `rateLimit` object of RateLimitSelection.
+type rateLimitField struct {
+	RateLimit *struct {
+		Cost      int       `json:"cost"`
+		Limit     int       `json:"limit"`
+		Remaining int       `json:"remaining"`
+		ResetAt   time.Time `json:"resetAt"`
+	} `json:"rateLimit"`
+}
+
+func (t *rateLimitTracker) updateFromField(field rateLimitField) {
+	if field.RateLimit == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = RateLimit{
+		Limit:     field.RateLimit.Limit,
+		Remaining: field.RateLimit.Remaining,
+		Cost:      field.RateLimit.Cost,
+		ResetAt:   field.RateLimit.ResetAt,
+	}
+}
+
+const secondaryRateLimitWait = time.Minute
+
+// rateLimitWait inspects a failed response and returns how long to wait before
+// the request can be retried. A nil error means the response is not rate limited,
+// a zero wait that it must not be retried.
+func rateLimitWait(resp *http.Response, body []byte, attempt int) (time.Duration, *RateLimitError) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, nil
+	}
+
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		wait := max(time.Duration(retryAfter)*time.Second, time.Second)
+		return wait, &RateLimitError{ResetAt: time.Now().Add(wait), Secondary: true}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			// Without a reset time the exhausted quota can't be waited out.
+			return 0, &RateLimitError{}
+		}
+		resetAt := time.Unix(reset, 0)
+		return max(time.Until(resetAt), 0), &RateLimitError{ResetAt: resetAt}
+	}
+
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		wait := secondaryRateLimitWait << attempt
+		return wait, &RateLimitError{ResetAt: time.Now().Add(wait), Secondary: true}
+	}
+	return 0, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
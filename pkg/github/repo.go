@@ -1,44 +1,98 @@
 package github
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"cmp"
+	"context"
 	"time"
 )
 
 type Repository struct {
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	URL         string    `json:"url"`
-	Stars       int       `json:"stargazerCount"`
-	Forks       int       `json:"forkCount"`
-	Language    string    `json:"primaryLanguage"`
-	Readme      string    `json:"readme"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	Owner       string `json:"owner"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Stars       int    `json:"stargazerCount"`
+	Forks       int    `json:"forkCount"`
+	Language    string `json:"primaryLanguage"`
+	IsFork      bool   `json:"isFork"`
+	IsArchived  bool   `json:"isArchived"`
+	IsTemplate  bool   `json:"isTemplate"`
+	// Visibility is PUBLIC, PRIVATE or INTERNAL.
+	Visibility       string   `json:"visibility"`
+	OpenIssues       int      `json:"openIssues"`
+	OpenPullRequests int      `json:"openPullRequests"`
+	License          string   `json:"license"`
+	Topics           []string `json:"topics"`
+	// DefaultBranch is empty for repositories without commits.
+	DefaultBranch string `json:"defaultBranch"`
+	// DiskUsage is the size of the repository in kilobytes.
+	DiskUsage int       `json:"diskUsage"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
-func GetRepositories(token, username string) ([]Repository, error) {
+// FullName returns the owner/name form of the repository name.
+func (r Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+type RepositoryList struct {
+	Repositories []Repository
+	TotalCount   int
+}
+
+const repositoriesPageSize = 100
+
+// GetRepositories pages through all repositories of the user. When limit is
+// positive, fetching stops once limit repositories have been collected.
+func GetRepositories(ctx context.Context, client *Client, username string, limit int) (RepositoryList, error) {
 	const query = `
-    query($username: String!) {
+    query($username: String!, $first: Int!, $after: String) {` + RateLimitSelection + `
         user(login: $username) {
-            repositories(first: 100) {
+            repositories(first: $first, after: $after) {
+                totalCount
+                pageInfo {
+                    hasNextPage
+                    endCursor
+                }
                 nodes {
+                    owner {
+                        login
+                    }
                     name
                     description
                     url
                     stargazerCount
                     forkCount
+                    isFork
+                    isArchived
+                    isTemplate
+                    visibility
+                    issues(states: OPEN) {
+                        totalCount
+                    }
+                    pullRequests(states: OPEN) {
+                        totalCount
+                    }
+                    licenseInfo {
+                        spdxId
+                        name
+                    }
+                    repositoryTopics(first: 10) {
+                        nodes {
+                            topic {
+                                name
+                            }
+                        }
+                    }
+                    diskUsage
+                    createdAt
                     pushedAt
                     primaryLanguage {
                         name
                     }
-                    object(expression: "HEAD:README.md") {
-                        ... on Blob {
-                            text
-                        }
+                    defaultBranchRef {
+                        name
                     }
                 }
             }
@@ -46,90 +100,126 @@ func GetRepositories(token, username string) ([]Repository, error) {
     }
     `
 
-	requestBody := map[string]interface{}{
-		"query": query,
-		"variables": map[string]interface{}{
-			"username": username,
-		},
-	}
-
-	body, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", "https://api.github.com/graphql", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("expected status code %d, got %d body: %s", http.StatusOK, resp.StatusCode, string(bodyBytes))
-	}
-
 	var response struct {
-		Data struct {
-			User struct {
-				Repositories struct {
-					Nodes []struct {
-						Name        string    `json:"name"`
-						Description string    `json:"description"`
-						URL         string    `json:"url"`
-						Stars       int       `json:"stargazerCount"`
-						Forks       int       `json:"forkCount"`
-						UpdatedAt   time.Time `json:"pushedAt"`
-						Language    struct {
-							Name string `json:"name"`
-						} `json:"primaryLanguage"`
-						Object struct {
-							Text string `json:"text"`
-						} `json:"object"`
-					} `json:"nodes"`
-				} `json:"repositories"`
-			} `json:"user"`
-		} `json:"data"`
+		User *struct {
+			Repositories struct {
+				TotalCount int `json:"totalCount"`
+				PageInfo   struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					Owner struct {
+						Login string `json:"login"`
+					} `json:"owner"`
+					Name        string `json:"name"`
+					Description string `json:"description"`
+					URL         string `json:"url"`
+					Stars       int    `json:"stargazerCount"`
+					Forks       int    `json:"forkCount"`
+					IsFork      bool   `json:"isFork"`
+					IsArchived  bool   `json:"isArchived"`
+					IsTemplate  bool   `json:"isTemplate"`
+					Visibility  string `json:"visibility"`
+					Issues      struct {
+						TotalCount int `json:"totalCount"`
+					} `json:"issues"`
+					PullRequests struct {
+						TotalCount int `json:"totalCount"`
+					} `json:"pullRequests"`
+					LicenseInfo *struct {
+						SpdxID string `json:"spdxId"`
+						Name   string `json:"name"`
+					} `json:"licenseInfo"`
+					RepositoryTopics struct {
+						Nodes []struct {
+							Topic struct {
+								Name string `json:"name"`
+							} `json:"topic"`
+						} `json:"nodes"`
+					} `json:"repositoryTopics"`
+					DiskUsage int       `json:"diskUsage"`
+					CreatedAt time.Time `json:"createdAt"`
+					UpdatedAt time.Time `json:"pushedAt"`
+					Language  struct {
+						Name string `json:"name"`
+					} `json:"primaryLanguage"`
+					DefaultBranchRef *struct {
+						Name string `json:"name"`
+					} `json:"defaultBranchRef"`
+				} `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"user"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
-	}
+	var list RepositoryList
+	var cursor interface{}
+	for {
+		pageSize := repositoriesPageSize
+		if limit > 0 {
+			pageSize = min(pageSize, limit-len(list.Repositories))
+		}
+		variables := map[string]interface{}{
+			"username": username,
+			"first":    pageSize,
+			"after":    cursor,
+		}
+		response.User = nil
+		if err := client.Query(ctx, query, variables, &response); err != nil {
+			return RepositoryList{}, UserError(err, username)
+		}
+		if response.User == nil {
+			return RepositoryList{}, &UserNotFoundError{Login: username}
+		}
+
+		repositories := response.User.Repositories
+		list.TotalCount = repositories.TotalCount
+		for _, node := range repositories.Nodes {
+			repo := Repository{
+				Owner:            cmp.Or(node.Owner.Login, username),
+				Name:             node.Name,
+				Description:      node.Description,
+				Stars:            node.Stars,
+				Forks:            node.Forks,
+				Language:         node.Language.Name,
+				IsFork:           node.IsFork,
+				IsArchived:       node.IsArchived,
+				IsTemplate:       node.IsTemplate,
+				Visibility:       node.Visibility,
+				OpenIssues:       node.Issues.TotalCount,
+				OpenPullRequests: node.PullRequests.TotalCount,
+				DiskUsage:        node.DiskUsage,
+				CreatedAt:        node.CreatedAt,
+				UpdatedAt:        node.UpdatedAt,
+			}
+			if license := node.LicenseInfo; license != nil {
+				// Unrecognized licenses have the NOASSERTION SPDX identifier.
+				repo.License = license.SpdxID
+				if repo.License == "" || repo.License == "NOASSERTION" {
+					repo.License = license.Name
+				}
+			}
+			for _, topic := range node.RepositoryTopics.Nodes {
+				repo.Topics = append(repo.Topics, topic.Topic.Name)
+			}
+			repo.URL = cmp.Or(node.URL, client.WebURL(repo.Owner, repo.Name))
+			if node.DefaultBranchRef != nil {
+				repo.DefaultBranch = node.DefaultBranchRef.Name
+			}
+			list.Repositories = append(list.Repositories, repo)
+		}
 
-	var repos []Repository
-	for _, node := range response.Data.User.Repositories.Nodes {
-		repo := Repository{
-			Name:        node.Name,
-			Description: node.Description,
-			URL:         node.URL,
-			Stars:       node.Stars,
-			Forks:       node.Forks,
-			Language:    node.Language.Name,
-			Readme:      node.Object.Text,
-			UpdatedAt:   node.UpdatedAt,
+		if !repositories.PageInfo.HasNextPage || (limit > 0 && len(list.Repositories) >= limit) {
+			break
 		}
-		repos = append(repos, repo)
+		cursor = repositories.PageInfo.EndCursor
 	}
 
-	return sortRepositories(repos), nil
+	SortRepositories(list.Repositories, SortByUpdated, true)
+	return list, nil
 }
 
-func sortRepositories(repos []Repository) []Repository {
-	for i := range len(repos) {
-		for j := i + 1; j < len(repos); j++ {
-			if repos[i].UpdatedAt.Before(repos[j].UpdatedAt) {
-				repos[i], repos[j] = repos[j], repos[i]
-			}
-		}
-	}
-	return repos
+// IsComplete reports whether every repository of the user has been fetched.
+func (l RepositoryList) IsComplete() bool {
+	return len(l.Repositories) >= l.TotalCount
 }
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SortField is the repository attribute repositories are sorted by.
+type SortField int
+
+const (
+	SortByName SortField = iota
+	SortByStars
+	SortByForks
+	SortByLanguage
+	SortByUpdated
+	sortFieldCount
+)
+
+func (f SortField) String() string {
+	switch f {
+	case SortByName:
+		return "name"
+	case SortByStars:
+		return "stars"
+	case SortByForks:
+		return "forks"
+	case SortByLanguage:
+		return "language"
+	case SortByUpdated:
+		return "updated"
+	}
+	return "unknown"
+}
+
+// Next returns the field following f, wrapping around after the last one.
+func (f SortField) Next() SortField {
+	return (f + 1) % sortFieldCount
+}
+
+// compare orders repositories by f in ascending order.
+func (f SortField) compare(a, b Repository) int {
+	switch f {
+	case SortByName:
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortByStars:
+		return cmp.Compare(a.Stars, b.Stars)
+	case SortByForks:
+		return cmp.Compare(a.Forks, b.Forks)
+	case SortByLanguage:
+		return cmp.Compare(strings.ToLower(a.Language), strings.ToLower(b.Language))
+	case SortByUpdated:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+	return 0
+}
+
+// SortRepositories sorts repos in place by field. Repositories that compare
+// equal keep their relative order.
+func SortRepositories(repos []Repository, field SortField, descending bool) {
+	slices.SortStableFunc(repos, func(a, b Repository) int {
+		if descending {
+			return field.compare(b, a)
+		}
+		return field.compare(a, b)
+	})
+}
@@ -0,0 +1,408 @@
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Link is a link or an image found in a markdown document.
+type Link struct {
+	Number int
+	Text   string
+	// URL is the absolute link target.
+	URL string
+	// Path is the repository path of the target for relative links, "" otherwise.
+	Path  string
+	Image bool
+}
+
+// Base locates a markdown document within a repository so that relative
+// links can be resolved.
+type Base struct {
+	// RepoURL is the web URL of the repository, e.g. https://github.com/owner/name
+	RepoURL string
+	Branch  string
+	// DocPath is the repository path of the document, e.g. docs/setup.md
+	DocPath string
+}
+
+var (
+	linkText = `((?:\\.|[^\[\]\\])*)`
+	// Link targets may be wrapped in <> and may be followed by a quoted title.
+	linkTarget = `\(\s*<?([^\s()<>]*)>?(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`
+
+	imagePattern        = regexp.MustCompile(`!\[` + linkText + `\]` + linkTarget)
+	linkPattern         = regexp.MustCompile(`\[` + linkText + `\]` + linkTarget)
+	refImagePattern     = regexp.MustCompile(`!\[` + linkText + `\]\[([^\]]*)\]`)
+	refLinkPattern      = regexp.MustCompile(`\[` + linkText + `\]\[([^\]]*)\]`)
+	refDefinitionRegexp = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:\s*<?(\S+?)>?(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*$`)
+	htmlImagePattern    = regexp.MustCompile(`(?i)<img\s[^>]*>`)
+	htmlLinkPattern     = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	htmlAttrPattern     = regexp.MustCompile(`(?i)\b(src|alt)\s*=\s*["']([^"']*)["']`)
+	htmlTagPattern      = regexp.MustCompile(`<[^>]+>`)
+	shortcutPattern     = regexp.MustCompile(`(!?)\[` + linkText + `\]`)
+	codeSpanPattern     = regexp.MustCompile("`+[^`]*`+")
+	fencePattern        = regexp.MustCompile("^ {0,3}(```|~~~)")
+	listItemPattern     = regexp.MustCompile(`^ {0,3}([-*+]|\d{1,9}[.)])(\s|$)`)
+)
+
+type rewriter struct {
+	base        Base
+	links       []Link
+	numbers     map[string]int
+	definitions map[string]string
+}
+
+// Rewrite resolves the links and images of a markdown document against base.
+// Links are replaced by their text followed by a [n] marker, images by a
+// labelled placeholder, and the numbered link targets are appended as footnotes.
+func Rewrite(src string, base Base) (string, []Link) {
+	r := &rewriter{
+		base:        base,
+		numbers:     make(map[string]int),
+		definitions: make(map[string]string),
+	}
+
+	lines := strings.Split(src, "\n")
+	lines = r.collectDefinitions(lines)
+
+	inFence, inList, inCode, afterBlank := false, false, false, true
+	for i, line := range lines {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		blank := strings.TrimSpace(line) == ""
+		switch {
+		case blank:
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			// Indented lines continue a list item or a paragraph, otherwise
+			// they are indented code.
+			inCode = inCode || afterBlank && !inList
+		case listItemPattern.MatchString(line):
+			inList, inCode = true, false
+		default:
+			// Only an unindented paragraph after a blank line ends a list.
+			inList = inList && (!afterBlank || line[0] == ' ')
+			inCode = false
+		}
+		afterBlank = blank
+		if !inCode {
+			lines[i] = r.rewriteLine(line)
+		}
+	}
+
+	out := strings.Join(lines, "\n")
+	if len(r.links) == 0 {
+		return out, nil
+	}
+	return out + "\n\n---\n\n" + footnotes(r.links), r.links
+}
+
+// collectDefinitions removes reference definitions such as `[logo]: img/logo.png`
+// and remembers their targets.
+func (r *rewriter) collectDefinitions(lines []string) []string {
+	kept := lines[:0]
+	inFence := false
+	for _, line := range lines {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+		}
+		if !inFence {
+			if match := refDefinitionRegexp.FindStringSubmatch(line); match != nil {
+				r.definitions[strings.ToLower(match[1])] = match[2]
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	return kept
+}
+
+// rewriteLine rewrites everything outside of inline code spans.
+func (r *rewriter) rewriteLine(line string) string {
+	var out strings.Builder
+	last := 0
+	for _, span := range codeSpanPattern.FindAllStringIndex(line, -1) {
+		out.WriteString(r.rewriteText(line[last:span[0]]))
+		out.WriteString(line[span[0]:span[1]])
+		last = span[1]
+	}
+	out.WriteString(r.rewriteText(line[last:]))
+	return out.String()
+}
+
+// match is a link or an image found in a line.
+type match struct {
+	start, end int
+	image      bool
+	html       bool
+	target     string
+	// alt is the alt text of images, links keep the offsets of their text so
+	// that the images nested in it can be rendered.
+	alt                string
+	textStart, textEnd int
+}
+
+func (r *rewriter) rewriteText(text string) string {
+	images := r.findImages(text)
+	// Images nested in links, such as badges, are part of the link text. They
+	// are masked so that the link patterns match around them.
+	masked := []byte(text)
+	for _, image := range images {
+		for i := image.start; i < image.end; i++ {
+			masked[i] = 0
+		}
+	}
+	links := r.findLinks(string(masked))
+
+	// Targets are numbered in document order, a link before the images in its text.
+	var out strings.Builder
+	last := 0
+	next := 0
+	for _, link := range links {
+		for ; next < len(images) && images[next].start < link.start; next++ {
+			out.WriteString(text[last:images[next].start])
+			out.WriteString(r.image(images[next].alt, images[next].target))
+			last = images[next].end
+		}
+		nested := next
+		for next < len(images) && images[next].end <= link.end {
+			next++
+		}
+		out.WriteString(text[last:link.start])
+		out.WriteString(r.link(text, link, images[nested:next]))
+		last = link.end
+	}
+	for ; next < len(images); next++ {
+		out.WriteString(text[last:images[next].start])
+		out.WriteString(r.image(images[next].alt, images[next].target))
+		last = images[next].end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// findImages finds the markdown, reference and HTML images of text.
+func (r *rewriter) findImages(text string) []match {
+	var found []match
+	for _, loc := range htmlImagePattern.FindAllStringIndex(text, -1) {
+		image := match{start: loc[0], end: loc[1], image: true, html: true}
+		for _, attr := range htmlAttrPattern.FindAllStringSubmatch(text[loc[0]:loc[1]], -1) {
+			if strings.EqualFold(attr[1], "src") {
+				image.target = attr[2]
+			} else {
+				image.alt = attr[2]
+			}
+		}
+		found = append(found, image)
+	}
+	for _, m := range imagePattern.FindAllStringSubmatchIndex(text, -1) {
+		found = append(found, match{start: m[0], end: m[1], image: true, alt: text[m[2]:m[3]], target: text[m[4]:m[5]]})
+	}
+	for _, m := range refImagePattern.FindAllStringSubmatchIndex(text, -1) {
+		if target, ok := r.definition(text[m[2]:m[3]], text[m[4]:m[5]]); ok {
+			found = append(found, match{start: m[0], end: m[1], image: true, alt: text[m[2]:m[3]], target: target})
+		}
+	}
+	for _, m := range shortcutPattern.FindAllStringSubmatchIndex(text, -1) {
+		if m[3] > m[2] && isShortcut(text, m[0], m[1]) {
+			if target, ok := r.definition(text[m[4]:m[5]], ""); ok {
+				found = append(found, match{start: m[0], end: m[1], image: true, alt: text[m[4]:m[5]], target: target})
+			}
+		}
+	}
+	return firstMatches(found)
+}
+
+// findLinks finds the markdown, reference and HTML links of text.
+func (r *rewriter) findLinks(text string) []match {
+	var found []match
+	for _, m := range htmlLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+		found = append(found, match{start: m[0], end: m[1], html: true, target: text[m[2]:m[3]], textStart: m[4], textEnd: m[5]})
+	}
+	for _, m := range linkPattern.FindAllStringSubmatchIndex(text, -1) {
+		found = append(found, match{start: m[0], end: m[1], target: text[m[4]:m[5]], textStart: m[2], textEnd: m[3]})
+	}
+	for _, m := range refLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+		if target, ok := r.definition(text[m[2]:m[3]], text[m[4]:m[5]]); ok {
+			found = append(found, match{start: m[0], end: m[1], target: target, textStart: m[2], textEnd: m[3]})
+		}
+	}
+	for _, m := range shortcutPattern.FindAllStringSubmatchIndex(text, -1) {
+		if m[3] == m[2] && isShortcut(text, m[0], m[1]) {
+			if target, ok := r.definition(text[m[4]:m[5]], ""); ok {
+				found = append(found, match{start: m[0], end: m[1], target: target, textStart: m[4], textEnd: m[5]})
+			}
+		}
+	}
+	return firstMatches(found)
+}
+
+// isShortcut reports whether text[start:end] is a shortcut reference such as
+// `[ref]`, rather than an escaped bracket or the start of another link form.
+func isShortcut(text string, start, end int) bool {
+	if start > 0 && (text[start-1] == '\\' || text[start-1] == ']') {
+		return false
+	}
+	return end == len(text) || text[end] != '(' && text[end] != '['
+}
+
+// firstMatches sorts matches by offset and drops those overlapping an earlier,
+// or at the same offset a longer, match.
+func firstMatches(found []match) []match {
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].start != found[j].start {
+			return found[i].start < found[j].start
+		}
+		return found[i].end > found[j].end
+	})
+	kept := found[:0]
+	for _, m := range found {
+		if len(kept) == 0 || m.start >= kept[len(kept)-1].end {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// definition looks up the target of `[text][ref]`, an empty ref refers to text.
+func (r *rewriter) definition(text, ref string) (string, bool) {
+	if ref == "" {
+		ref = text
+	}
+	target, ok := r.definitions[strings.ToLower(ref)]
+	return target, ok
+}
+
+func (r *rewriter) image(alt, src string) string {
+	if src == "" {
+		return ""
+	}
+	n := r.add(alt, src, true)
+	label := "image"
+	if alt != "" {
+		label += ": " + alt
+	}
+	return fmt.Sprintf("*⧉ %s* \\[%d\\]", Escape(label), n)
+}
+
+// link renders a link of text with the images nested in its text.
+func (r *rewriter) link(text string, link match, nested []match) string {
+	// The footnote text reads the nested images as their alt text.
+	var plain strings.Builder
+	last := link.textStart
+	for _, image := range nested {
+		plain.WriteString(text[last:image.start])
+		plain.WriteString(image.alt)
+		last = image.end
+	}
+	plain.WriteString(text[last:link.textEnd])
+	var n int
+	if link.target != "" {
+		n = r.add(strings.TrimSpace(stripTags(plain.String(), link.html)), link.target, false)
+	}
+
+	var rendered strings.Builder
+	last = link.textStart
+	for _, image := range nested {
+		rendered.WriteString(stripTags(text[last:image.start], link.html))
+		rendered.WriteString(r.image(image.alt, image.target))
+		last = image.end
+	}
+	rendered.WriteString(stripTags(text[last:link.textEnd], link.html))
+	label := strings.TrimSpace(rendered.String())
+	switch {
+	case link.target == "":
+		return label
+	case label == "":
+		return fmt.Sprintf("\\[%d\\]", n)
+	}
+	return fmt.Sprintf("%s \\[%d\\]", label, n)
+}
+
+// stripTags removes the tags from the text of HTML links.
+func stripTags(text string, html bool) string {
+	if !html {
+		return text
+	}
+	return htmlTagPattern.ReplaceAllString(text, "")
+}
+
+func (r *rewriter) add(text, target string, image bool) int {
+	absolute, repoPath := r.base.Resolve(target, image)
+	if n, ok := r.numbers[absolute]; ok {
+		return n
+	}
+	n := len(r.links) + 1
+	r.numbers[absolute] = n
+	r.links = append(r.links, Link{
+		Number: n,
+		Text:   text,
+		URL:    absolute,
+		Path:   repoPath,
+		Image:  image,
+	})
+	return n
+}
+
+// Resolve turns a link target into an absolute URL. Relative targets are
+// resolved against the document directory and their repository path is returned too.
+func (b Base) Resolve(target string, image bool) (string, string) {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "" || strings.HasPrefix(target, "//") || b.RepoURL == "" {
+		return target, ""
+	}
+
+	repoURL := strings.TrimSuffix(b.RepoURL, "/")
+	branch := b.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	if u.Path == "" {
+		// Anchor within the document itself.
+		return fmt.Sprintf("%s/blob/%s/%s%s", repoURL, branch, b.DocPath, target), ""
+	}
+
+	repoPath := u.Path
+	if !strings.HasPrefix(repoPath, "/") {
+		repoPath = path.Join(path.Dir(b.DocPath), repoPath)
+	}
+	repoPath = strings.TrimPrefix(path.Clean("/"+repoPath), "/")
+
+	kind := "blob"
+	if image {
+		kind = "raw"
+	}
+	absolute := fmt.Sprintf("%s/%s/%s/%s", repoURL, kind, branch, repoPath)
+	if u.RawQuery != "" {
+		absolute += "?" + u.RawQuery
+	}
+	if u.Fragment != "" {
+		absolute += "#" + u.Fragment
+	}
+	return absolute, repoPath
+}
+
+func footnotes(links []Link) string {
+	var out strings.Builder
+	out.WriteString("**Links**\n\n")
+	for _, link := range links {
+		fmt.Fprintf(&out, "- \\[%d\\] %s\n", link.Number, Escape(link.URL))
+	}
+	return out.String()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, "`", "\\`", `<`, `\<`)
+
+// Escape escapes text so that it renders literally in markdown.
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
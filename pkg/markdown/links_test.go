@@ -0,0 +1,164 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	base := Base{RepoURL: "https://github.com/octocat/hello", Branch: "main", DocPath: "docs/README.md"}
+	tests := []struct {
+		name string
+		src  string
+		want string
+		urls []string
+	}{
+		{
+			name: "relative",
+			src:  "See the [guide](GUIDE.md) and the [license](/LICENSE).",
+			want: `See the guide \[1\] and the license \[2\].`,
+			urls: []string{
+				"https://github.com/octocat/hello/blob/main/docs/GUIDE.md",
+				"https://github.com/octocat/hello/blob/main/LICENSE",
+			},
+		},
+		{
+			name: "parent directory",
+			src:  "[Back](../README.md#usage)",
+			want: `Back \[1\]`,
+			urls: []string{"https://github.com/octocat/hello/blob/main/README.md#usage"},
+		},
+		{
+			name: "anchor",
+			src:  "[Usage](#usage)",
+			want: `Usage \[1\]`,
+			urls: []string{"https://github.com/octocat/hello/blob/main/docs/README.md#usage"},
+		},
+		{
+			name: "absolute and repeated",
+			src:  `[one](https://example.com "Example") and [two](<https://example.com>)`,
+			want: `one \[1\] and two \[1\]`,
+			urls: []string{"https://example.com"},
+		},
+		{
+			name: "image",
+			src:  "![Logo](img/logo.png) ![](https://example.com/x.png)",
+			want: `*⧉ image: Logo* \[1\] *⧉ image* \[2\]`,
+			urls: []string{"https://github.com/octocat/hello/raw/main/docs/img/logo.png", "https://example.com/x.png"},
+		},
+		{
+			name: "badge inside link",
+			src:  "[![CI](https://ci.example.com/badge.svg)](https://ci.example.com) [docs](GUIDE.md)",
+			want: `*⧉ image: CI* \[2\] \[1\] docs \[3\]`,
+			urls: []string{
+				"https://ci.example.com",
+				"https://ci.example.com/badge.svg",
+				"https://github.com/octocat/hello/blob/main/docs/GUIDE.md",
+			},
+		},
+		{
+			name: "document order across kinds",
+			src:  `<img src="a.png"> [b](b.md) ![c][c] <a href="https://d.example.com">d</a> [e][]` + "\n\n[c]: c.png\n[e]: https://e.example.com",
+			want: `*⧉ image* \[1\] b \[2\] *⧉ image: c* \[3\] d \[4\] e \[5\]` + "\n",
+			urls: []string{
+				"https://github.com/octocat/hello/raw/main/docs/a.png",
+				"https://github.com/octocat/hello/blob/main/docs/b.md",
+				"https://github.com/octocat/hello/raw/main/docs/c.png",
+				"https://d.example.com",
+				"https://e.example.com",
+			},
+		},
+		{
+			name: "reference",
+			src:  "[full][site], [collapsed][] and [Shortcut], not [unknown] or \\[escaped]\n\n[site]: https://example.com/site\n[collapsed]: https://example.com/collapsed \"Title\"\n[shortcut]: <https://example.com/shortcut>",
+			want: `full \[1\], collapsed \[2\] and Shortcut \[3\], not [unknown] or \[escaped]` + "\n",
+			urls: []string{"https://example.com/site", "https://example.com/collapsed", "https://example.com/shortcut"},
+		},
+		{
+			name: "reference image",
+			src:  "![Logo][logo] ![logo]\n\n[logo]: img/logo.png",
+			want: `*⧉ image: Logo* \[1\] *⧉ image: logo* \[1\]` + "\n",
+			urls: []string{"https://github.com/octocat/hello/raw/main/docs/img/logo.png"},
+		},
+		{
+			name: "code span",
+			src:  "`[a](a.md)` and [b](b.md)",
+			want: "`[a](a.md)` and b \\[1\\]",
+			urls: []string{"https://github.com/octocat/hello/blob/main/docs/b.md"},
+		},
+		{
+			name: "fenced block",
+			src:  "```\n[a](a.md)\n[ref]: https://example.com\n```\n[ref]",
+			want: "```\n[a](a.md)\n[ref]: https://example.com\n```\n[ref]",
+		},
+		{
+			name: "indented block",
+			src:  "Example:\n\n    [a](a.md)\n\n        [b](b.md)\n\nDone.",
+			want: "Example:\n\n    [a](a.md)\n\n        [b](b.md)\n\nDone.",
+		},
+		{
+			name: "paragraph continuation",
+			src:  "A long line\n    [a](a.md)",
+			want: "A long line\n    a \\[1\\]",
+			urls: []string{"https://github.com/octocat/hello/blob/main/docs/a.md"},
+		},
+		{
+			name: "nested list",
+			src:  "- Setup\n    - [Install](INSTALL.md)\n\n    [Configure](CONFIG.md)\n1. [Run](RUN.md)\n\n        [Tips](TIPS.md)\n\nEnd\n\n    [code](code.md)",
+			want: "- Setup\n    - Install \\[1\\]\n\n    Configure \\[2\\]\n1. Run \\[3\\]\n\n        Tips \\[4\\]\n\nEnd\n\n    [code](code.md)",
+			urls: []string{
+				"https://github.com/octocat/hello/blob/main/docs/INSTALL.md",
+				"https://github.com/octocat/hello/blob/main/docs/CONFIG.md",
+				"https://github.com/octocat/hello/blob/main/docs/RUN.md",
+				"https://github.com/octocat/hello/blob/main/docs/TIPS.md",
+			},
+		},
+		{
+			name: "html",
+			src:  `<a href="https://example.com"><img src="logo.png" alt="Logo"> <b>Home</b></a> <img alt="none">`,
+			want: `*⧉ image: Logo* \[2\] Home \[1\] `,
+			urls: []string{"https://example.com", "https://github.com/octocat/hello/raw/main/docs/logo.png"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, links := Rewrite(tt.src, base)
+			text, _, _ := strings.Cut(out, "\n\n---\n\n")
+			if text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			var urls []string
+			for i, link := range links {
+				if link.Number != i+1 {
+					t.Errorf("link %d is numbered %d", i+1, link.Number)
+				}
+				urls = append(urls, link.URL)
+			}
+			if !reflect.DeepEqual(urls, tt.urls) {
+				t.Errorf("urls = %q, want %q", urls, tt.urls)
+			}
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	base := Base{RepoURL: "https://github.com/octocat/hello/", DocPath: "docs/README.md"}
+	tests := []struct {
+		target, url, path string
+		image             bool
+	}{
+		{"setup.md", "https://github.com/octocat/hello/blob/HEAD/docs/setup.md", "docs/setup.md", false},
+		{"../src/main.go?plain=1#L10", "https://github.com/octocat/hello/blob/HEAD/src/main.go?plain=1#L10", "src/main.go", false},
+		{"../../../etc/passwd", "https://github.com/octocat/hello/blob/HEAD/etc/passwd", "etc/passwd", false},
+		{"/img/logo.png", "https://github.com/octocat/hello/raw/HEAD/img/logo.png", "img/logo.png", true},
+		{"mailto:octocat@github.com", "mailto:octocat@github.com", "", false},
+		{"//cdn.example.com/x.png", "//cdn.example.com/x.png", "", true},
+	}
+	for _, tt := range tests {
+		url, path := base.Resolve(tt.target, tt.image)
+		if url != tt.url || path != tt.path {
+			t.Errorf("Resolve(%q) = %q, %q, want %q, %q", tt.target, url, path, tt.url, tt.path)
+		}
+	}
+}
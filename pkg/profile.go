@@ -0,0 +1,51 @@
+package contribution
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+// ColorMode chooses whether the output is colored, the --color flag.
+type ColorMode int
+
+const (
+	// ColorAuto detects the color support of the terminal, honoring NO_COLOR.
+	ColorAuto ColorMode = iota
+	// ColorAlways colors the output even when it isn't a terminal.
+	ColorAlways
+	// ColorNever renders plain glyphs.
+	ColorNever
+)
+
+var colorModeNames = []string{"auto", "always", "never"}
+
+func (m ColorMode) String() string {
+	return colorModeNames[m]
+}
+
+func ParseColorMode(name string) (ColorMode, error) {
+	i := slices.Index(colorModeNames, strings.ToLower(name))
+	if i < 0 {
+		return 0, fmt.Errorf("unknown color mode %q, expected one of %s", name, strings.Join(colorModeNames, ", "))
+	}
+	return ColorMode(i), nil
+}
+
+// Profile returns the color profile of output. Colors forced by ColorAlways
+// keep the detected depth when the terminal supports colors.
+func (m ColorMode) Profile(output io.Writer, env []string) colorprofile.Profile {
+	switch m {
+	case ColorNever:
+		return colorprofile.ASCII
+	case ColorAlways:
+		if profile := colorprofile.Detect(output, env); profile >= colorprofile.ANSI {
+			return profile
+		}
+		return colorprofile.TrueColor
+	}
+	return colorprofile.Detect(output, env)
+}
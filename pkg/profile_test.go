@@ -0,0 +1,63 @@
+package contribution
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+func TestColorModeProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		env  []string
+		want map[ColorMode]colorprofile.Profile
+	}{
+		{
+			name: "not a terminal",
+			env:  []string{"TERM=xterm-256color"},
+			want: map[ColorMode]colorprofile.Profile{ColorAuto: colorprofile.NoTTY, ColorAlways: colorprofile.TrueColor, ColorNever: colorprofile.ASCII},
+		},
+		{
+			name: "no color",
+			env:  []string{"TERM=xterm-256color", "NO_COLOR=1"},
+			want: map[ColorMode]colorprofile.Profile{ColorAuto: colorprofile.NoTTY, ColorAlways: colorprofile.TrueColor, ColorNever: colorprofile.ASCII},
+		},
+		{
+			name: "forced 256 colors",
+			env:  []string{"TERM=xterm-256color", "CLICOLOR_FORCE=1"},
+			want: map[ColorMode]colorprofile.Profile{ColorAuto: colorprofile.ANSI256, ColorAlways: colorprofile.ANSI256, ColorNever: colorprofile.ASCII},
+		},
+		{
+			name: "forced 16 colors",
+			env:  []string{"TERM=xterm", "CLICOLOR_FORCE=1"},
+			want: map[ColorMode]colorprofile.Profile{ColorAuto: colorprofile.ANSI, ColorAlways: colorprofile.ANSI, ColorNever: colorprofile.ASCII},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for mode, want := range tt.want {
+				if got := mode.Profile(&bytes.Buffer{}, tt.env); got != want {
+					t.Errorf("%s profile = %s, want %s", mode, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	for _, mode := range []ColorMode{ColorAuto, ColorAlways, ColorNever} {
+		got, err := ParseColorMode(mode.String())
+		if err != nil || got != mode {
+			t.Errorf("ParseColorMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if got, err := ParseColorMode("NEVER"); err != nil || got != ColorNever {
+		t.Errorf("ParseColorMode(%q) = %v, %v, want never", "NEVER", got, err)
+	}
+
+	_, err := ParseColorMode("sometimes")
+	if want := `unknown color mode "sometimes", expected one of auto, always, never`; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
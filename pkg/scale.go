@@ -0,0 +1,119 @@
+package contribution
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ColorScale maps contribution counts to the intensity levels of the palette,
+// 0 for no contributions up to 4 for the busiest days.
+type ColorScale int
+
+const (
+	// ScaleGitHub uses the contributionLevel of the API, which are quartiles
+	// computed by GitHub, and falls back to ScaleQuartile without them.
+	ScaleGitHub ColorScale = iota
+	// ScaleQuartile splits the days with contributions into quartiles.
+	ScaleQuartile
+	// ScaleFixed uses fixed thresholds of 1, 3 and 5 contributions.
+	ScaleFixed
+)
+
+var colorScaleNames = []string{"github", "quartile", "fixed"}
+
+func (s ColorScale) String() string {
+	return colorScaleNames[s]
+}
+
+func ParseColorScale(name string) (ColorScale, error) {
+	i := slices.Index(colorScaleNames, strings.ToLower(name))
+	if i < 0 {
+		return 0, fmt.Errorf("unknown color scale %q, expected one of %s", name, strings.Join(colorScaleNames, ", "))
+	}
+	return ColorScale(i), nil
+}
+
+// contributionLevels maps the contributionLevel values of the API.
+var contributionLevels = map[string]uint8{
+	"NONE":            0,
+	"FIRST_QUARTILE":  1,
+	"SECOND_QUARTILE": 2,
+	"THIRD_QUARTILE":  3,
+	"FOURTH_QUARTILE": 4,
+}
+
+// dayLevels returns the level of every day of the matrix.
+func (s ColorScale) dayLevels(matrix [][]ContributionDay) func(ContributionDay) int {
+	var counts []uint64
+	withLevels := true
+	for _, row := range matrix {
+		for _, day := range row {
+			if day.ContributionCount > 0 {
+				counts = append(counts, day.ContributionCount)
+				withLevels = withLevels && day.Level > 0
+			}
+		}
+	}
+	switch {
+	case s == ScaleGitHub && withLevels:
+		return func(day ContributionDay) int { return int(day.Level) }
+	case s == ScaleFixed:
+		return func(day ContributionDay) int { return fixedLevel(day.ContributionCount) }
+	}
+	level := quartileLevels(counts)
+	return func(day ContributionDay) int { return level(day.ContributionCount) }
+}
+
+// countLevels returns the level of counts other than single days, such as
+// weekly totals.
+func (s ColorScale) countLevels(counts []uint64) func(uint64) int {
+	if s == ScaleFixed {
+		return fixedLevel
+	}
+	var nonZero []uint64
+	for _, count := range counts {
+		if count > 0 {
+			nonZero = append(nonZero, count)
+		}
+	}
+	return quartileLevels(nonZero)
+}
+
+func fixedLevel(count uint64) int {
+	switch {
+	case count == 0:
+		return 0
+	case count == 1:
+		return 1
+	case count <= 3:
+		return 2
+	case count <= 5:
+		return 3
+	}
+	return 4
+}
+
+// quartileLevels splits the non-zero counts into quartiles.
+func quartileLevels(counts []uint64) func(uint64) int {
+	counts = slices.Clone(counts)
+	slices.Sort(counts)
+	quartile := func(q int) uint64 {
+		if len(counts) == 0 {
+			return 0
+		}
+		return counts[(len(counts)-1)*q/4]
+	}
+	thresholds := []uint64{quartile(1), quartile(2), quartile(3)}
+	return func(count uint64) int {
+		if count == 0 {
+			return 0
+		}
+		for i, threshold := range thresholds {
+			if count <= threshold {
+				return i + 1
+			}
+		}
+		return 4
+	}
+}
@@ -0,0 +1,77 @@
+package contribution
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuartileLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []uint64
+		levels map[uint64]int
+	}{
+		{"no contributions", nil, map[uint64]int{0: 0, 1: 4}},
+		{"single value", []uint64{5}, map[uint64]int{0: 0, 1: 1, 5: 1, 6: 4}},
+		{"ties", []uint64{3, 3, 3, 3}, map[uint64]int{0: 0, 3: 1, 4: 4}},
+		{"ties at the top", []uint64{1, 2, 9, 9, 9}, map[uint64]int{1: 1, 2: 1, 9: 2, 10: 4}},
+		{"quartiles", []uint64{8, 1, 2, 3, 4, 5, 6, 7, 9}, map[uint64]int{1: 1, 3: 1, 4: 2, 5: 2, 6: 3, 7: 3, 8: 4, 9: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := quartileLevels(tt.counts)
+			for count, want := range tt.levels {
+				if got := level(count); got != want {
+					t.Errorf("level(%d) = %d, want %d", count, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDayLevels(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	withLevels := calendarOf(start, 0, 1, 10)
+	withLevels.Matrix[2][0].Level = 4
+	withLevels.Matrix[3][0].Level = 2
+	days := withLevels.Days()
+
+	tests := []struct {
+		name     string
+		scale    ColorScale
+		calendar Calendar
+		want     []int
+	}{
+		{"github levels", ScaleGitHub, withLevels, []int{0, 4, 2}},
+		{"github without levels", ScaleGitHub, calendarOf(start, 0, 1, 10), []int{0, 1, 4}},
+		{"quartile", ScaleQuartile, withLevels, []int{0, 1, 4}},
+		{"fixed", ScaleFixed, withLevels, []int{0, 1, 4}},
+		{"all zero", ScaleQuartile, calendarOf(start, 0, 0, 0), []int{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := tt.scale.dayLevels(tt.calendar.Matrix)
+			for i, day := range tt.calendar.Days() {
+				if got := level(day); got != tt.want[i] {
+					t.Errorf("level of %s = %d, want %d", days[i].Date.Format(dateLayout), got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseColorScale(t *testing.T) {
+	for _, name := range colorScaleNames {
+		scale, err := ParseColorScale(name)
+		if err != nil || scale.String() != name {
+			t.Errorf("ParseColorScale(%q) = %v, %v", name, scale, err)
+		}
+	}
+	if scale, err := ParseColorScale("Fixed"); err != nil || scale != ScaleFixed {
+		t.Errorf("ParseColorScale(%q) = %v, %v, want fixed", "Fixed", scale, err)
+	}
+	_, err := ParseColorScale("linear")
+	if want := `unknown color scale "linear", expected one of github, quartile, fixed`; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,107 @@
+package contribution
+
+import (
+	"time"
+)
+
+// Stats summarizes the contributions of a calendar.
+type Stats struct {
+	// From and To are the first and the last day of the calendar.
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Total uint64 `json:"total"`
+	// CurrentStreak counts the days with contributions up to the last day of
+	// the calendar. A quiet today doesn't break the streak yet.
+	CurrentStreak int `json:"currentStreak"`
+	LongestStreak int `json:"longestStreak"`
+	// BusiestWeekday has the most contributions, "" when there are none.
+	BusiestWeekday string       `json:"busiestWeekday"`
+	BestDay        DayTotal     `json:"bestDay"`
+	Months         []MonthTotal `json:"months"`
+}
+
+type DayTotal struct {
+	// Date is in the YYYY-MM-DD form, "" when there are no contributions.
+	Date  string `json:"date"`
+	Count uint64 `json:"count"`
+}
+
+type MonthTotal struct {
+	// Month is in the YYYY-MM form.
+	Month string `json:"month"`
+	Count uint64 `json:"count"`
+}
+
+// Days returns the days of the calendar in date order, without padding.
+func (c Calendar) Days() []ContributionDay {
+	var days []ContributionDay
+	if len(c.Matrix) == 0 {
+		return days
+	}
+	for week := range c.Matrix[0] {
+		for _, row := range c.Matrix {
+			if week < len(row) && !row[week].IsPadding() {
+				days = append(days, row[week])
+			}
+		}
+	}
+	return days
+}
+
+// ComputeStats summarizes the calendar, today decides whether the last day
+// may still extend the current streak.
+func ComputeStats(calendar Calendar, today time.Time) Stats {
+	days := calendar.Days()
+	stats := Stats{Total: calendar.Total}
+	if len(days) == 0 {
+		return stats
+	}
+	stats.From = days[0].Date.Format(dateLayout)
+	stats.To = days[len(days)-1].Date.Format(dateLayout)
+
+	var sum uint64
+	var weekdays [7]uint64
+	streak := 0
+	for _, day := range days {
+		sum += day.ContributionCount
+		weekdays[day.Weekday%7] += day.ContributionCount
+		if day.ContributionCount > stats.BestDay.Count {
+			stats.BestDay = DayTotal{Date: day.Date.Format(dateLayout), Count: day.ContributionCount}
+		}
+
+		month := day.Date.Format("2006-01")
+		if n := len(stats.Months); n == 0 || stats.Months[n-1].Month != month {
+			stats.Months = append(stats.Months, MonthTotal{Month: month})
+		}
+		stats.Months[len(stats.Months)-1].Count += day.ContributionCount
+
+		if day.ContributionCount == 0 {
+			streak = 0
+			continue
+		}
+		streak++
+		stats.LongestStreak = max(stats.LongestStreak, streak)
+	}
+	if stats.Total == 0 {
+		stats.Total = sum
+	}
+
+	last := days[len(days)-1]
+	if last.ContributionCount == 0 && last.Date.Format(dateLayout) == today.Format(dateLayout) {
+		days = days[:len(days)-1]
+	}
+	for i := len(days) - 1; i >= 0 && days[i].ContributionCount > 0; i-- {
+		stats.CurrentStreak++
+	}
+
+	busiest := 0
+	for weekday, count := range weekdays {
+		if count > weekdays[busiest] {
+			busiest = weekday
+		}
+	}
+	if weekdays[busiest] > 0 {
+		stats.BusiestWeekday = time.Weekday(busiest).String()
+	}
+	return stats
+}
@@ -0,0 +1,126 @@
+package contribution
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// calendarOf builds a calendar of consecutive days starting at start.
+func calendarOf(start time.Time, counts ...uint64) Calendar {
+	var days []ContributionDay
+	for i, count := range counts {
+		date := start.AddDate(0, 0, i)
+		days = append(days, ContributionDay{
+			ContributionCount: count,
+			Month:             uint8(date.Month()) - 1,
+			Weekday:           uint8(date.Weekday()),
+			Date:              date,
+		})
+	}
+	return Calendar{Matrix: MakeContributionMatrix(days)}
+}
+
+func TestComputeStats(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		calendar Calendar
+		today    time.Time
+		want     Stats
+	}{
+		{
+			name:     "streaks",
+			calendar: calendarOf(start, 1, 2, 3, 0, 4, 0, 5, 6),
+			today:    later,
+			want: Stats{
+				From: "2024-01-01", To: "2024-01-08", Total: 21,
+				CurrentStreak: 2, LongestStreak: 3,
+				BusiestWeekday: "Monday",
+				BestDay:        DayTotal{Date: "2024-01-08", Count: 6},
+				Months:         []MonthTotal{{Month: "2024-01", Count: 21}},
+			},
+		},
+		{
+			name:     "quiet today",
+			calendar: calendarOf(start, 1, 1, 0),
+			today:    start.AddDate(0, 0, 2).Add(15 * time.Hour),
+			want: Stats{
+				From: "2024-01-01", To: "2024-01-03", Total: 2,
+				CurrentStreak: 2, LongestStreak: 2,
+				BusiestWeekday: "Monday",
+				BestDay:        DayTotal{Date: "2024-01-01", Count: 1},
+				Months:         []MonthTotal{{Month: "2024-01", Count: 2}},
+			},
+		},
+		{
+			name:     "quiet past day",
+			calendar: calendarOf(start, 1, 1, 0),
+			today:    start.AddDate(0, 0, 3),
+			want: Stats{
+				From: "2024-01-01", To: "2024-01-03", Total: 2,
+				CurrentStreak: 0, LongestStreak: 2,
+				BusiestWeekday: "Monday",
+				BestDay:        DayTotal{Date: "2024-01-01", Count: 1},
+				Months:         []MonthTotal{{Month: "2024-01", Count: 2}},
+			},
+		},
+		{
+			name:     "busiest weekday",
+			calendar: calendarOf(start, 1, 2, 9, 1, 1, 0, 0, 1, 2, 1),
+			today:    later,
+			want: Stats{
+				From: "2024-01-01", To: "2024-01-10", Total: 18,
+				CurrentStreak: 3, LongestStreak: 5,
+				BusiestWeekday: "Wednesday",
+				BestDay:        DayTotal{Date: "2024-01-03", Count: 9},
+				Months:         []MonthTotal{{Month: "2024-01", Count: 18}},
+			},
+		},
+		{
+			name:     "year boundary",
+			calendar: calendarOf(time.Date(2023, time.December, 29, 0, 0, 0, 0, time.UTC), 0, 2, 3, 4, 1, 0),
+			today:    later,
+			want: Stats{
+				From: "2023-12-29", To: "2024-01-03", Total: 10,
+				CurrentStreak: 0, LongestStreak: 4,
+				BusiestWeekday: "Monday",
+				BestDay:        DayTotal{Date: "2024-01-01", Count: 4},
+				Months:         []MonthTotal{{Month: "2023-12", Count: 5}, {Month: "2024-01", Count: 5}},
+			},
+		},
+		{
+			name:     "no contributions",
+			calendar: calendarOf(start, 0, 0),
+			today:    later,
+			want: Stats{
+				From: "2024-01-01", To: "2024-01-02",
+				Months: []MonthTotal{{Month: "2024-01"}},
+			},
+		},
+		{
+			name:     "empty",
+			calendar: calendarOf(start),
+			today:    later,
+			want:     Stats{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeStats(tt.calendar, tt.today); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeStats() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeStatsKeepsTotal(t *testing.T) {
+	calendar := calendarOf(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), 1, 2)
+	// The total reported by GitHub includes contributions outside the days.
+	calendar.Total = 10
+	if got := ComputeStats(calendar, time.Now()).Total; got != 10 {
+		t.Errorf("Total = %d, want 10", got)
+	}
+}
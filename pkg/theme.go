@@ -0,0 +1,84 @@
+package contribution
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Theme colors the intensity levels of the calendar, from no contributions
+// to the busiest days. An empty color leaves the glyph uncolored.
+type Theme struct {
+	Name   string
+	Colors [5]string
+}
+
+var Themes = []Theme{
+	{Name: "classic", Colors: [5]string{"", "#9be9a8", "#40c463", "#2fb67d", "#1a936f"}},
+	{Name: "github-dark", Colors: [5]string{"#2d333b", "#0e4429", "#006d32", "#26a641", "#39d353"}},
+	{Name: "github-light", Colors: [5]string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}},
+	{Name: "halloween", Colors: [5]string{"#2d333b", "#631c03", "#bd561d", "#fa7a18", "#fddf68"}},
+	{Name: "colorblind-blue", Colors: [5]string{"#2d333b", "#0a3069", "#0969da", "#54aeff", "#b6e3ff"}},
+	{Name: "colorblind-orange", Colors: [5]string{"#2d333b", "#762d0a", "#bd561d", "#f0883e", "#ffc680"}},
+	{Name: "monochrome", Colors: [5]string{"#444c56", "#768390", "#adbac7", "#cdd9e5", "#ffffff"}},
+}
+
+// DefaultTheme keeps the original calendar colors.
+var DefaultTheme = Themes[0]
+
+func ThemeByName(name string) (Theme, error) {
+	var names []string
+	for _, theme := range Themes {
+		if strings.EqualFold(theme.Name, name) {
+			return theme, nil
+		}
+		names = append(names, theme.Name)
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// color returns the color of the level, nil when the level is uncolored.
+func (t Theme) color(level int) color.Color {
+	if t.Colors[level] == "" {
+		return nil
+	}
+	return lipgloss.Color(t.Colors[level])
+}
+
+var GlyphSets = map[string]DayDisplay{
+	"squares": squareDayDisplay,
+	"blocks":  {Empty: "·", Full: "█", Levels: []string{"·", "░", "▒", "▓", "█"}},
+	"dots":    {Empty: "·", Full: "●"},
+	"braille": {Empty: "⠂", Full: "⣿", Levels: []string{"⠂", "⣀", "⣤", "⣶", "⣿"}},
+}
+
+// ParseDayDisplay returns the named glyph set. Other values are custom glyph
+// sets of two glyphs, for empty and full days, or of one glyph per level.
+func ParseDayDisplay(value string) (DayDisplay, error) {
+	if glyphs, ok := GlyphSets[strings.ToLower(value)]; ok {
+		return glyphs, nil
+	}
+	var glyphs []string
+	for _, glyph := range value {
+		glyphs = append(glyphs, string(glyph))
+	}
+	switch len(glyphs) {
+	case 2:
+		return DayDisplay{Empty: glyphs[0], Full: glyphs[1]}, nil
+	case len(Theme{}.Colors):
+		return DayDisplay{Empty: glyphs[0], Full: glyphs[4], Levels: glyphs}, nil
+	}
+	return DayDisplay{}, fmt.Errorf("unknown glyphs %q, expected squares, blocks, dots, braille, two or five glyphs", value)
+}
+
+// FormatLegend renders the "Less □■■■■ More" legend of the levels.
+func FormatLegend(options CalendarOptions) string {
+	options = options.withDefaults()
+	legend := "Less "
+	for level := range options.Theme.Colors {
+		legend += options.cell(level, false)
+	}
+	return legend + " More"
+}
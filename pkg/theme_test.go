@@ -0,0 +1,66 @@
+package contribution
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThemeByName(t *testing.T) {
+	for _, theme := range Themes {
+		got, err := ThemeByName(theme.Name)
+		if err != nil || got != theme {
+			t.Errorf("ThemeByName(%q) = %v, %v", theme.Name, got, err)
+		}
+	}
+	if got, err := ThemeByName("GitHub-Dark"); err != nil || got.Name != "github-dark" {
+		t.Errorf("ThemeByName(%q) = %v, %v, want github-dark", "GitHub-Dark", got, err)
+	}
+
+	_, err := ThemeByName("solarized")
+	want := `unknown theme "solarized", expected one of classic, github-dark, github-light, halloween, colorblind-blue, colorblind-orange, monochrome`
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestParseDayDisplay(t *testing.T) {
+	tests := []struct {
+		value string
+		want  DayDisplay
+	}{
+		{"squares", squareDayDisplay},
+		{"Blocks", GlyphSets["blocks"]},
+		{"dots", GlyphSets["dots"]},
+		{"braille", GlyphSets["braille"]},
+		{"-+", DayDisplay{Empty: "-", Full: "+"}},
+		{"○●", DayDisplay{Empty: "○", Full: "●"}},
+		{" .oO@", DayDisplay{Empty: " ", Full: "@", Levels: []string{" ", ".", "o", "O", "@"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseDayDisplay(tt.value)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseDayDisplay(%q) = %+v, %v, want %+v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "x", "abc", "hearts", "123456"} {
+		_, err := ParseDayDisplay(value)
+		want := `unknown glyphs "` + value + `", expected squares, blocks, dots, braille, two or five glyphs`
+		if err == nil || err.Error() != want {
+			t.Errorf("ParseDayDisplay(%q) error = %v, want %q", value, err, want)
+		}
+	}
+}
+
+func TestDayDisplayGlyph(t *testing.T) {
+	dots := GlyphSets["dots"]
+	blocks := GlyphSets["blocks"]
+	for level, want := range []string{"·", "●", "●", "●", "●"} {
+		if got := dots.glyph(level); got != want {
+			t.Errorf("dots level %d = %q, want %q", level, got, want)
+		}
+		if got := blocks.glyph(level); got != blocks.Levels[level] {
+			t.Errorf("blocks level %d = %q, want %q", level, got, blocks.Levels[level])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	display "github-dashboard/pkg"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// breakdownRepositories is the number of listed repositories, the breakdown
+// is as tall as the calendar grid.
+const breakdownRepositories = display.Height/2 - 2
+
+const breakdownBarWidth = 12
+
+// breakdownView shows the contributions by type and the repositories the
+// user contributed to the most, whoever owns them.
+func breakdownView(breakdown display.Breakdown, username string, theme display.Theme, width int) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	if theme.Name == "" {
+		theme = display.DefaultTheme
+	}
+	barStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colors[2]))
+
+	totals := fmt.Sprintf("Commits %d · Pull requests %d · Issues %d · Reviews %d",
+		breakdown.Commits, breakdown.PullRequests, breakdown.Issues, breakdown.Reviews)
+	if breakdown.Restricted > 0 {
+		totals += fmt.Sprintf(" · Private %d", breakdown.Restricted)
+	}
+	lines := []string{ansi.Truncate(titleStyle.Render(totals)+hintStyle.Render("  i: calendar"), width, "…")}
+
+	repos := breakdown.Repositories
+	if len(repos) == 0 {
+		lines = append(lines, hintStyle.Render("No contributions to public repositories in this range."))
+		return lipgloss.NewStyle().Width(width).Height(display.Height / 2).Render(strings.Join(lines, "\n"))
+	}
+	repos = repos[:min(len(repos), breakdownRepositories)]
+
+	// Narrow terminals drop the bars, then the counts by type.
+	const countsWidth = 29
+	detailed := width >= 50
+	bars := width >= 80
+	nameWidth := width - 6
+	if detailed {
+		nameWidth = width - countsWidth
+	}
+	if bars {
+		nameWidth -= breakdownBarWidth + 2
+	}
+
+	header := fmt.Sprintf("%-*s %5s", nameWidth, "Repository", "Total")
+	if detailed {
+		header = fmt.Sprintf("%-*s %7s %5s %6s %7s", nameWidth, "Repository", "Commits", "PRs", "Issues", "Reviews")
+	}
+	lines = append(lines, hintStyle.Render(header))
+
+	busiest := repos[0].Total()
+	for _, repo := range repos {
+		name := ansi.Truncate(repo.Repository, nameWidth, "…")
+		padding := strings.Repeat(" ", max(nameWidth-ansi.StringWidth(name), 0))
+		if repo.Owner != "" && !strings.EqualFold(repo.Owner, username) {
+			// Repositories of other owners aren't part of the repository table.
+			name = hintStyle.Render(name)
+		}
+		line := name + padding + fmt.Sprintf(" %5d", repo.Total())
+		if detailed {
+			line = name + padding + fmt.Sprintf(" %7d %5d %6d %7d", repo.Commits, repo.PullRequests, repo.Issues, repo.Reviews)
+		}
+		if bars && busiest > 0 {
+			line += "  " + barStyle.Render(strings.Repeat("■", max(repo.Total()*breakdownBarWidth/busiest, 1)))
+		}
+		lines = append(lines, line)
+	}
+	return lipgloss.NewStyle().Width(width).Height(display.Height / 2).Render(strings.Join(lines, "\n"))
+}
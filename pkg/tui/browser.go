@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"log"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// isWebURL reports whether target is an http or https URL. Links of READMEs
+// are untrusted, other schemes could start any registered handler.
+func isWebURL(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+// openURL opens url in the default web browser, anything but a web URL is
+// refused.
+func openURL(url string) tea.Cmd {
+	if !isWebURL(url) {
+		log.Printf("[UI] Refusing to open %s", url)
+		return nil
+	}
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+		if err := cmd.Start(); err != nil {
+			log.Printf("[UI] Failed to open %s: %v", url, err)
+			return nil
+		}
+		go cmd.Wait()
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package tui
+
+import "testing"
+
+func TestIsWebURL(t *testing.T) {
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"https://github.com/octocat/hello", true},
+		{"HTTP://example.com", true},
+		{"file:///etc/passwd", false},
+		{"smb://host/share", false},
+		{"vscode://open?file=x", false},
+		{"javascript:alert(1)", false},
+		{"https:relative", false},
+		{"docs/README.md", false},
+	}
+	for _, tt := range tests {
+		if got := isWebURL(tt.target); got != tt.want {
+			t.Errorf("isWebURL(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	display "github-dashboard/pkg"
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const dayCacheSize = 32
+
+// dayListHeight is the number of listed contributions, the list takes the
+// place of the month header and the seven weekdays of the grid.
+const dayListHeight = 7
+
+type dayMsg struct {
+	date          string
+	contributions []display.DayContribution
+	err           error
+}
+
+// CalendarModel is the contribution calendar with a cursor over its days. Like
+// the bubbles components it's embedded in the dashboard model, its View
+// renders a string within the size set with SetSize.
+type CalendarModel struct {
+	client   *github.Client
+	username string
+	options  display.CalendarOptions
+	data     display.Calendar
+	days     []display.ContributionDay
+	cursor   int
+	focused  bool
+	// width is the width of the grid, available the width it may share with
+	// the stats or the selected day beside it.
+	width     int
+	available int
+	// dayOpen lists the contributions of the selected day in place of the grid.
+	dayOpen   bool
+	dayCursor int
+	dayOffset int
+	dayLists  *lru[string, []display.DayContribution]
+	dayErrs   map[string]error
+	pending   map[string]bool
+}
+
+func newCalendarModel(client *github.Client, username string, options display.CalendarOptions) *CalendarModel {
+	return &CalendarModel{
+		client:   client,
+		username: username,
+		options:  options,
+		dayLists: newLRU[string, []display.DayContribution](dayCacheSize),
+		dayErrs:  make(map[string]error),
+		pending:  make(map[string]bool),
+	}
+}
+
+// setData shows a new calendar, keeping the selected day when it's still part of it.
+func (m *CalendarModel) setData(data display.Calendar) {
+	prev, selected := m.selected()
+	m.data = data
+	m.days = data.Days()
+	m.cursor = len(m.days) - 1
+	found := false
+	for i, day := range m.days {
+		if selected && day.Date.Equal(prev.Date) {
+			m.cursor, found = i, true
+		}
+	}
+	if !found {
+		m.dayOpen = false
+	}
+}
+
+func (m *CalendarModel) selected() (display.ContributionDay, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.days) {
+		return display.ContributionDay{}, false
+	}
+	return m.days[m.cursor], true
+}
+
+func (m *CalendarModel) move(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.days)-1), 0)
+}
+
+func dayKey(day display.ContributionDay) string {
+	return day.Date.Format("2006-01-02")
+}
+
+func (m *CalendarModel) fetch(day display.ContributionDay) tea.Cmd {
+	key := dayKey(day)
+	if m.pending[key] {
+		return nil
+	}
+	m.pending[key] = true
+	delete(m.dayErrs, key)
+	client, username := m.client, m.username
+	return func() tea.Msg {
+		contributions, err := display.GetDayContributions(context.Background(), client, username, day.Date)
+		return dayMsg{date: key, contributions: contributions, err: err}
+	}
+}
+
+func (m *CalendarModel) loaded(msg dayMsg) {
+	delete(m.pending, msg.date)
+	if msg.err != nil {
+		log.Printf("[UI] Contributions of %s failed: %v", msg.date, msg.err)
+		m.dayErrs[msg.date] = msg.err
+		return
+	}
+	m.dayLists.add(msg.date, msg.contributions)
+}
+
+// openDay lists the contributions of the selected day, fetching them when
+// they aren't cached yet.
+func (m *CalendarModel) openDay() tea.Cmd {
+	day, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	m.dayOpen = true
+	m.dayCursor, m.dayOffset = 0, 0
+	if _, ok := m.dayLists.get(dayKey(day)); ok {
+		return nil
+	}
+	return m.fetch(day)
+}
+
+func (m *CalendarModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update moves the cursor of the focused calendar and lists the contributions
+// of the days it fetched.
+func (m *CalendarModel) Update(msg tea.Msg) (*CalendarModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !m.focused {
+			return m, nil
+		}
+		if m.dayOpen {
+			return m, m.updateDayList(msg)
+		}
+		return m, m.updateGrid(msg)
+	case dayMsg:
+		m.loaded(msg)
+	}
+	return m, nil
+}
+
+func (m *CalendarModel) updateGrid(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "c":
+		m.focused = false
+	case "left", "h":
+		m.move(-7)
+	case "right", "l":
+		m.move(7)
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.days) - 1
+	case "enter":
+		return m.openDay()
+	}
+	return nil
+}
+
+func (m *CalendarModel) updateDayList(msg tea.KeyMsg) tea.Cmd {
+	day, _ := m.selected()
+	list, _ := m.dayLists.get(dayKey(day))
+	switch msg.String() {
+	case "esc", "backspace":
+		m.dayOpen = false
+	case "c":
+		m.dayOpen = false
+		m.focused = false
+	case "up", "k":
+		m.dayCursor = max(m.dayCursor-1, 0)
+	case "down", "j":
+		m.dayCursor = max(min(m.dayCursor+1, len(list)-1), 0)
+	case "enter":
+		if m.dayErrs[dayKey(day)] != nil {
+			return m.fetch(day)
+		}
+		if m.dayCursor < len(list) && list[m.dayCursor].URL != "" {
+			return openURL(list[m.dayCursor].URL)
+		}
+	}
+	return nil
+}
+
+func formatContributions(n uint64) string {
+	if n == 1 {
+		return "1 contribution"
+	}
+	return fmt.Sprintf("%d contributions", n)
+}
+
+// SetSize sets the width of the grid and the width available to show the
+// stats or the selected day beside it.
+func (m *CalendarModel) SetSize(width, available int) {
+	m.width, m.available = width, available
+}
+
+// View renders the grid, or the contributions of the selected day, and the
+// stats or the selected day beside it when there's room.
+func (m *CalendarModel) View() string {
+	width, available := m.width, m.available
+	if m.dayOpen {
+		return m.dayListView(width)
+	}
+
+	options := m.options
+	day, ok := m.selected()
+	if m.focused && ok {
+		options.Selected = day.Date
+	}
+	calendar := display.FitCalendar(m.data.Matrix, width, options)
+	if len(m.days) == 0 {
+		return calendar
+	}
+
+	beside := lipgloss.Width(calendar)+len(statsGap)+statsWidth <= available
+	switch {
+	case m.focused && beside:
+		return lipgloss.JoinHorizontal(lipgloss.Top, calendar, statsGap, dayView(day))
+	case m.focused:
+		row := fmt.Sprintf("%s · %s  enter: list  esc: leave", day.Date.Format("Mon, Jan 2 2006"), formatContributions(day.ContributionCount))
+		return calendar + "\n" + statsValueStyle.Render(ansi.Truncate(row, width, "…"))
+	}
+	stats := display.ComputeStats(m.data, time.Now())
+	legend := display.FormatLegend(m.options)
+	if beside {
+		return lipgloss.JoinHorizontal(lipgloss.Top, calendar, statsGap, statsView(stats)+"\n"+legend)
+	}
+	return calendar + "\n" + legend + "  " + statsRow(stats, width-lipgloss.Width(legend)-2)
+}
+
+// dayView describes the selected day beside the calendar.
+func dayView(day display.ContributionDay) string {
+	return strings.Join([]string{
+		statsValueStyle.Bold(true).Render(day.Date.Format("Monday, Jan 2 2006")),
+		statsValueStyle.Render(formatContributions(day.ContributionCount)),
+		"",
+		statsLabelStyle.Render("←/→ week  ↑/↓ day"),
+		statsLabelStyle.Render("enter: list contributions"),
+		statsLabelStyle.Render("esc: leave the calendar"),
+	}, "\n")
+}
+
+func (m *CalendarModel) dayListView(width int) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(true)
+
+	day, _ := m.selected()
+	key := dayKey(day)
+	title := fmt.Sprintf("%s · %s", day.Date.Format("Mon, Jan 2 2006"), formatContributions(day.ContributionCount))
+	lines := []string{ansi.Truncate(titleStyle.Render(title)+hintStyle.Render("  enter: open  esc: back"), width, "…")}
+
+	list, ok := m.dayLists.get(key)
+	switch {
+	case m.dayErrs[key] != nil:
+		lines = append(lines, errorStyle.Render(ansi.Truncate(fmt.Sprintf("Failed to load contributions: %v", m.dayErrs[key]), width, "…")),
+			hintStyle.Render("Press enter to retry"))
+	case !ok:
+		lines = append(lines, hintStyle.Render("Loading contributions ..."))
+	case len(list) == 0:
+		lines = append(lines, hintStyle.Render("No public commits, pull requests, issues or reviews."))
+	}
+
+	m.dayCursor = max(min(m.dayCursor, len(list)-1), 0)
+	if m.dayCursor < m.dayOffset {
+		m.dayOffset = m.dayCursor
+	} else if m.dayCursor >= m.dayOffset+dayListHeight {
+		m.dayOffset = m.dayCursor - dayListHeight + 1
+	}
+	for i := m.dayOffset; i < len(list) && i < m.dayOffset+dayListHeight; i++ {
+		line := ansi.Truncate(formatDayContribution(list[i]), width, "…")
+		if i == m.dayCursor {
+			line = selectedStyle.Width(width).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lipgloss.NewStyle().Width(width).Height(dayListHeight + 1).Render(strings.Join(lines, "\n"))
+}
+
+func formatDayContribution(c display.DayContribution) string {
+	if c.Kind == display.KindCommits {
+		commits := "commits"
+		if c.Count == 1 {
+			commits = "commit"
+		}
+		return fmt.Sprintf("%-7s %s  %d %s", c.Kind, c.Repository, c.Count, commits)
+	}
+	return fmt.Sprintf("%-7s %s#%d %s", c.Kind, c.Repository, c.Number, c.Title)
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"testing"
+
+	display "github-dashboard/pkg"
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestCalendarUpdate(t *testing.T) {
+	m := newCalendarModel(github.NewClient(""), "octocat", display.CalendarOptions{})
+	m.setData(testCalendar())
+	last := len(m.days) - 1
+	key := func(code rune, text string) tea.Msg { return tea.KeyPressMsg{Code: code, Text: text} }
+
+	// Keys are ignored until the calendar is focused.
+	m, _ = m.Update(key(tea.KeyLeft, ""))
+	if m.cursor != last {
+		t.Fatalf("unfocused cursor moved to %d", m.cursor)
+	}
+
+	m.focused = true
+	for _, step := range []struct {
+		msg    tea.Msg
+		cursor int
+	}{
+		{key(tea.KeyLeft, ""), last - 7},
+		{key('k', "k"), last - 8},
+		{key('g', "g"), 0},
+		{key(tea.KeyUp, ""), 0},
+		{key('l', "l"), 7},
+		{key('G', "G"), last},
+		{key(tea.KeyDown, ""), last},
+	} {
+		m, _ = m.Update(step.msg)
+		if m.cursor != step.cursor {
+			t.Errorf("after %v cursor = %d, want %d", step.msg, m.cursor, step.cursor)
+		}
+	}
+
+	var cmd tea.Cmd
+	m, cmd = m.Update(key(tea.KeyEnter, ""))
+	if !m.dayOpen || cmd == nil {
+		t.Fatalf("enter: dayOpen = %v, fetch = %v", m.dayOpen, cmd != nil)
+	}
+	day, _ := m.selected()
+	contributions := []display.DayContribution{{Kind: display.KindCommits, Repository: "octocat/hello-world", Count: 2}}
+	m, _ = m.Update(dayMsg{date: dayKey(day), contributions: contributions})
+	if list, ok := m.dayLists.get(dayKey(day)); !ok || len(list) != 1 {
+		t.Errorf("day list = %v, %v", list, ok)
+	}
+
+	m, _ = m.Update(key(tea.KeyEscape, ""))
+	if m.dayOpen || !m.focused {
+		t.Errorf("esc in the day list: dayOpen = %v, focused = %v", m.dayOpen, m.focused)
+	}
+	m, _ = m.Update(key(tea.KeyEscape, ""))
+	if m.focused {
+		t.Error("esc didn't leave the calendar")
+	}
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github-dashboard/pkg/config"
+	"github-dashboard/pkg/github"
+)
+
+// column describes a column the repository table can show.
+type column struct {
+	name  string
+	title string
+	width int
+	value func(github.Repository) string
+	// sortable columns show the sort indicator when the table is sorted by sort.
+	sortable bool
+	sort     github.SortField
+}
+
+var availableColumns = []column{
+	{name: "name", title: "Name", width: 20, value: func(r github.Repository) string { return r.Name }, sortable: true, sort: github.SortByName},
+	{name: "owner", title: "Owner", width: 12, value: func(r github.Repository) string { return r.Owner }},
+	{name: "description", title: "Description", width: 30, value: func(r github.Repository) string { return r.Description }},
+	{name: "language", title: "Language", width: 12, value: func(r github.Repository) string { return r.Language }, sortable: true, sort: github.SortByLanguage},
+	{name: "updated", title: "Updated", width: 9, value: func(r github.Repository) string { return formatTimeAgo(r.UpdatedAt) }, sortable: true, sort: github.SortByUpdated},
+	{name: "stars", title: "Stars", width: 7, value: func(r github.Repository) string { return strconv.Itoa(r.Stars) }, sortable: true, sort: github.SortByStars},
+	{name: "forks", title: "Forks", width: 7, value: func(r github.Repository) string { return strconv.Itoa(r.Forks) }, sortable: true, sort: github.SortByForks},
+	{name: "visibility", title: "Visibility", width: 10, value: func(r github.Repository) string { return strings.ToLower(r.Visibility) }},
+	{name: "flags", title: "Flags", width: 16, value: formatFlags},
+	{name: "issues", title: "Issues", width: 6, value: func(r github.Repository) string { return strconv.Itoa(r.OpenIssues) }},
+	{name: "prs", title: "PRs", width: 5, value: func(r github.Repository) string { return strconv.Itoa(r.OpenPullRequests) }},
+	{name: "license", title: "License", width: 12, value: func(r github.Repository) string { return r.License }},
+	{name: "topics", title: "Topics", width: 20, value: func(r github.Repository) string { return strings.Join(r.Topics, ", ") }},
+	{name: "branch", title: "Branch", width: 10, value: func(r github.Repository) string { return r.DefaultBranch }},
+	{name: "size", title: "Size", width: 8, value: func(r github.Repository) string { return formatSize(r.DiskUsage) }},
+	{name: "created", title: "Created", width: 10, value: func(r github.Repository) string { return r.CreatedAt.Format("2006-01-02") }},
+}
+
+var defaultColumns = []string{"name", "description", "language", "updated", "stars", "forks"}
+
+func findColumn(name string) (column, bool) {
+	for _, c := range availableColumns {
+		if strings.EqualFold(c.name, name) {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// ValidateColumns reports configured columns the table doesn't know.
+func ValidateColumns(columns []config.Column) error {
+	for _, c := range columns {
+		if _, ok := findColumn(c.Name); !ok {
+			names := make([]string, 0, len(availableColumns))
+			for _, available := range availableColumns {
+				names = append(names, available.name)
+			}
+			return fmt.Errorf("unknown column %q, available columns: %s", c.Name, strings.Join(names, ", "))
+		}
+		if c.Width < 0 {
+			return fmt.Errorf("column %q: negative width %d", c.Name, c.Width)
+		}
+	}
+	return nil
+}
+
+// tableColumns resolves the configured columns, falling back to the defaults.
+func tableColumns(configured []config.Column) []column {
+	if len(configured) == 0 {
+		for _, name := range defaultColumns {
+			configured = append(configured, config.Column{Name: name})
+		}
+	}
+	columns := []column{}
+	for _, c := range configured {
+		resolved, ok := findColumn(c.Name)
+		if !ok {
+			continue
+		}
+		if c.Width > 0 {
+			resolved.width = c.Width
+		}
+		columns = append(columns, resolved)
+	}
+	return columns
+}
+
+func formatFlags(r github.Repository) string {
+	var flags []string
+	if r.IsFork {
+		flags = append(flags, "fork")
+	}
+	if r.IsArchived {
+		flags = append(flags, "archived")
+	}
+	if r.IsTemplate {
+		flags = append(flags, "template")
+	}
+	return strings.Join(flags, ",")
+}
+
+// formatSize formats a size in kilobytes.
+func formatSize(kilobytes int) string {
+	switch {
+	case kilobytes >= 1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(kilobytes)/(1024*1024))
+	case kilobytes >= 1024:
+		return fmt.Sprintf("%.1f MB", float64(kilobytes)/1024)
+	}
+	return fmt.Sprintf("%d KB", kilobytes)
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github-dashboard/pkg/github"
+
+	"charm.land/lipgloss/v2"
+)
+
+var errTerminalTooSmall = errors.New("Terminal too small")
+
+// errorGuidance explains what the user can do about err.
+func errorGuidance(err error) string {
+	var (
+		userNotFound *github.UserNotFoundError
+		authErr      *github.AuthError
+		scopeErr     *github.ScopeError
+		rateLimitErr *github.RateLimitError
+		networkErr   *github.NetworkError
+		gqlErrors    github.GraphQLErrors
+	)
+
+	switch {
+	case errors.Is(err, github.ErrCacheMiss):
+		return "Nothing is cached for this user yet. Run once without --offline to populate the cache."
+	case errors.As(err, &userNotFound):
+		return fmt.Sprintf("Check the spelling of %q, it must be a GitHub login, not a display name.", userNotFound.Login)
+	case errors.As(err, &authErr):
+		return "GITHUB_TOKEN was rejected, it may be expired or revoked. Create a new token and export it again."
+	case errors.As(err, &scopeErr):
+		if len(scopeErr.Required) == 0 {
+			return "The token lacks a scope required by the query. Grant the missing scope and try again."
+		}
+		return fmt.Sprintf("Token lacks %s scope. Grant it in the token settings and try again.", strings.Join(scopeErr.Required, " or "))
+	case errors.As(err, &rateLimitErr):
+		if rateLimitErr.ResetAt.IsZero() {
+			return "The API quota is exhausted. Wait a while before retrying."
+		}
+		return fmt.Sprintf("The API quota is exhausted. Retry after %s.", rateLimitErr.ResetAt.Local().Format("15:04:05"))
+	case errors.As(err, &networkErr), errors.Is(err, context.DeadlineExceeded):
+		return "Could not reach GitHub. Check your connection or the --api-url setting."
+	case errors.As(err, &gqlErrors):
+		return "GitHub rejected the query."
+	}
+	return ""
+}
+
+func isRetryable(err error) bool {
+	return !errors.Is(err, errTerminalTooSmall)
+}
+
+func errorView(err error) string {
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render
+
+	view := fmt.Sprintf("\n  Error: %s\n", textStyle(err.Error()))
+	if guidance := errorGuidance(err); guidance != "" {
+		view += fmt.Sprintf("\n  %s\n", hintStyle(guidance))
+	}
+	if isRetryable(err) {
+		return view + "\n  Press 'r' to retry or 'q' to quit\n"
+	}
+	return view + "\n  Press 'q' to quit\n"
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	contribution "github-dashboard/pkg"
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// panel identifies an independently fetched data source of the dashboard.
+type panel int
+
+const (
+	panelContributions panel = 1 << iota
+	panelRepositories
+
+	allPanels = panelContributions | panelRepositories
+)
+
+func (p panel) String() string {
+	switch p {
+	case panelContributions:
+		return "Contributions"
+	case panelRepositories:
+		return "Repositories"
+	}
+	return "Panels"
+}
+
+// panels lists the single panels contained in the set p.
+func (p panel) panels() []panel {
+	var panels []panel
+	for single := panelContributions; single <= panelRepositories; single <<= 1 {
+		if p&single != 0 {
+			panels = append(panels, single)
+		}
+	}
+	return panels
+}
+
+type panelState struct {
+	loading bool
+	loaded  bool
+	// fromCache is set while the shown data comes from the on-disk cache.
+	fromCache bool
+	err       error
+	updatedAt time.Time
+}
+
+type contributionsMsg struct {
+	calendar contribution.Calendar
+	// rng is the requested range, responses for a previously shown range are dropped.
+	rng       contribution.Range
+	err       error
+	fromCache bool
+	fetchedAt time.Time
+}
+
+type repositoriesMsg struct {
+	list      github.RepositoryList
+	err       error
+	fromCache bool
+	fetchedAt time.Time
+}
+
+// fetchPanel queries a single panel. With github.CacheOnly policy the result
+// comes from the on-disk cache and is flagged as such. fetchedAt of the message
+// is when the API served the data, which is older than the fetch for data the
+// cache still considered fresh.
+func fetchPanel(config Config, policy github.CachePolicy, p panel) tea.Cmd {
+	fromCache := policy == github.CacheOnly
+
+	switch p {
+	case panelContributions:
+		return func() tea.Msg {
+			var fetchedAt time.Time
+			client := config.Client.WithCachePolicy(policy).WithFetchTime(&fetchedAt)
+			calendar, err := contribution.GetContributionsFromApi(context.Background(), client, config.Username, config.Range)
+			if err != nil {
+				return contributionsMsg{rng: config.Range, err: err, fromCache: fromCache}
+			}
+			return contributionsMsg{calendar: calendar, rng: config.Range, fromCache: fromCache, fetchedAt: fetchedAt}
+		}
+	case panelRepositories:
+		return func() tea.Msg {
+			var fetchedAt time.Time
+			client := config.Client.WithCachePolicy(policy).WithFetchTime(&fetchedAt)
+			list, err := github.GetRepositories(context.Background(), client, config.Username, config.MaxRepositories)
+			return repositoriesMsg{list: list, err: err, fromCache: fromCache, fetchedAt: fetchedAt}
+		}
+	}
+	return nil
+}
+
+// fetchPanels queries every panel of the set p concurrently.
+func fetchPanels(config Config, policy github.CachePolicy, p panel) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, single := range p.panels() {
+		cmds = append(cmds, fetchPanel(config, policy, single))
+	}
+	return tea.Batch(cmds...)
+}
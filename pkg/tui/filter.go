@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// repositoryFilter narrows the repository table. Free text terms fuzzy match
+// the name and the description, qualifiers such as `lang:go`, `stars:>10`,
+// `updated:<30d`, `fork:false`, `archived:false` or `topic:cli` match
+// repository attributes.
+type repositoryFilter struct {
+	terms      []string
+	predicates []func(github.Repository) bool
+}
+
+// parseFilter parses query, invalid qualifiers are reported and left out of the filter.
+func parseFilter(query string, now time.Time) (repositoryFilter, error) {
+	var f repositoryFilter
+	var errs []error
+	for _, token := range strings.Fields(query) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			f.terms = append(f.terms, strings.ToLower(token))
+			continue
+		}
+
+		var predicate func(github.Repository) bool
+		var err error
+		switch strings.ToLower(key) {
+		case "lang", "language":
+			predicate = func(r github.Repository) bool { return strings.EqualFold(r.Language, value) }
+		case "stars":
+			predicate, err = numberPredicate(value, func(r github.Repository) int { return r.Stars })
+		case "forks":
+			predicate, err = numberPredicate(value, func(r github.Repository) int { return r.Forks })
+		case "updated":
+			predicate, err = agePredicate(value, now)
+		case "fork":
+			predicate, err = boolPredicate(value, func(r github.Repository) bool { return r.IsFork })
+		case "archived":
+			predicate, err = boolPredicate(value, func(r github.Repository) bool { return r.IsArchived })
+		case "template":
+			predicate, err = boolPredicate(value, func(r github.Repository) bool { return r.IsTemplate })
+		case "visibility", "is":
+			predicate = func(r github.Repository) bool { return strings.EqualFold(r.Visibility, value) }
+		case "license":
+			predicate = func(r github.Repository) bool { return strings.EqualFold(r.License, value) }
+		case "topic":
+			predicate = func(r github.Repository) bool {
+				return slices.ContainsFunc(r.Topics, func(topic string) bool { return strings.EqualFold(topic, value) })
+			}
+		case "issues":
+			predicate, err = numberPredicate(value, func(r github.Repository) int { return r.OpenIssues })
+		case "prs":
+			predicate, err = numberPredicate(value, func(r github.Repository) int { return r.OpenPullRequests })
+		default:
+			err = errors.New("unknown qualifier")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", token, err))
+			continue
+		}
+		f.predicates = append(f.predicates, predicate)
+	}
+	return f, errors.Join(errs...)
+}
+
+func (f repositoryFilter) isEmpty() bool {
+	return len(f.terms) == 0 && len(f.predicates) == 0
+}
+
+func (f repositoryFilter) match(repo github.Repository) bool {
+	for _, predicate := range f.predicates {
+		if !predicate(repo) {
+			return false
+		}
+	}
+	name := strings.ToLower(repo.Name)
+	words := strings.FieldsFunc(strings.ToLower(repo.Description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, term := range f.terms {
+		if !fuzzyMatch(term, name) && !matchesAny(term, words) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f repositoryFilter) apply(repos []github.Repository) []github.Repository {
+	if f.isEmpty() {
+		return repos
+	}
+	matched := []github.Repository{}
+	for _, repo := range repos {
+		if f.match(repo) {
+			matched = append(matched, repo)
+		}
+	}
+	return matched
+}
+
+// filteredRepositories applies the filter prompt to the loaded repositories.
+func (m *Model) filteredRepositories() github.RepositoryList {
+	var f repositoryFilter
+	f, m.filterErr = parseFilter(m.filterInput.Value(), time.Now())
+	if f.isEmpty() {
+		return m.repositories
+	}
+	matched := f.apply(m.repositories.Repositories)
+	return github.RepositoryList{Repositories: matched, TotalCount: len(matched)}
+}
+
+// updateFilter edits the filter prompt, the table is narrowed as you type.
+func (m Model) updateFilter(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+		return m, nil
+	case "esc":
+		m.filtering = false
+		m.filterInput.Blur()
+		m.filterInput.SetValue("")
+		return m.rebuildBrowser()
+	case "up", "down", "pgup", "pgdown":
+		return m, m.browserModel.update(msg, m.visible.Repositories)
+	}
+
+	query := m.filterInput.Value()
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if m.filterInput.Value() == query {
+		return m, cmd
+	}
+	m, rebuildCmd := m.rebuildBrowser()
+	return m, tea.Batch(cmd, rebuildCmd)
+}
+
+func (m Model) filterView() string {
+	if !m.filtering && m.filterInput.Value() == "" {
+		return ""
+	}
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	view := " " + m.filterInput.View()
+	if !m.filtering {
+		view = " " + hintStyle.Render("/ ") + m.filterInput.Value()
+	}
+	view += hintStyle.Render(fmt.Sprintf("  %d of %d repositories", len(m.visible.Repositories), len(m.repositories.Repositories)))
+	if m.filterErr != nil {
+		view += "  " + errorStyle.Render(strings.ReplaceAll(m.filterErr.Error(), "\n", "; "))
+	}
+	return view
+}
+
+func matchesAny(term string, words []string) bool {
+	for _, word := range words {
+		if fuzzyMatch(term, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyMatch reports whether the characters of term appear in text in order.
+func fuzzyMatch(term, text string) bool {
+	for _, r := range term {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
+// splitComparison splits ">=10" into the operator and the operand, "=" is the default.
+func splitComparison(value string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			return op, rest
+		}
+	}
+	return "=", value
+}
+
+func compare(op string, a, b int64) bool {
+	switch op {
+	case ">=":
+		return a >= b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case "<":
+		return a < b
+	}
+	return a == b
+}
+
+func numberPredicate(value string, field func(github.Repository) int) (func(github.Repository) bool, error) {
+	op, operand := splitComparison(value)
+	n, err := strconv.ParseInt(operand, 10, 64)
+	if err != nil {
+		return nil, errors.New("expected a number such as >10")
+	}
+	return func(r github.Repository) bool { return compare(op, int64(field(r)), n) }, nil
+}
+
+// agePredicate compares the time since the last push, `updated:<30d` matches
+// repositories pushed to within the last 30 days.
+func agePredicate(value string, now time.Time) (func(github.Repository) bool, error) {
+	op, operand := splitComparison(value)
+	age, err := parseAge(operand)
+	if err != nil {
+		return nil, err
+	}
+	return func(r github.Repository) bool { return compare(op, int64(now.Sub(r.UpdatedAt)), int64(age)) }, nil
+}
+
+// parseAge parses ages such as 12h, 30d, 2w, 6m or 1y.
+func parseAge(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+		'm': 30 * 24 * time.Hour,
+		'y': 365 * 24 * time.Hour,
+	}
+	if len(value) < 2 {
+		return 0, errors.New("expected an age such as <30d")
+	}
+	unit, ok := units[value[len(value)-1]]
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if !ok || err != nil {
+		return 0, errors.New("expected an age such as <30d")
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func boolPredicate(value string, field func(github.Repository) bool) (func(github.Repository) bool, error) {
+	want, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.New("expected true or false")
+	}
+	return func(r github.Repository) bool { return field(r) == want }, nil
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github-dashboard/pkg/github"
+)
+
+func TestParseFilter(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repos := []github.Repository{
+		{Name: "dashboard", Description: "GitHub contributions in the terminal", Language: "Go", Stars: 120, Forks: 4, UpdatedAt: now.Add(-2 * day), Topics: []string{"cli", "tui"}},
+		{Name: "dotfiles", Description: "My shell setup", Language: "Shell", Stars: 3, UpdatedAt: now.Add(-400 * day), IsArchived: true},
+		{Name: "linguist", Description: "Language savant", Language: "Ruby", Stars: 10, Forks: 50, UpdatedAt: now.Add(-40 * day), IsFork: true},
+		{Name: "website", Description: "Personal site", Language: "TypeScript", Stars: 11, UpdatedAt: now.Add(-6 * time.Hour), Visibility: "PRIVATE"},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"dashboard", "dotfiles", "linguist", "website"}},
+		{"lang:go", []string{"dashboard"}},
+		{"language:RUBY", []string{"linguist"}},
+		{"stars:>10", []string{"dashboard", "website"}},
+		{"stars:>=10", []string{"dashboard", "linguist", "website"}},
+		{"stars:10", []string{"linguist"}},
+		{"stars:<10", []string{"dotfiles"}},
+		{"forks:>10", []string{"linguist"}},
+		{"updated:<30d", []string{"dashboard", "website"}},
+		{"updated:<12h", []string{"website"}},
+		{"updated:>1y", []string{"dotfiles"}},
+		{"updated:>=1m", []string{"dotfiles", "linguist"}},
+		{"fork:false archived:false", []string{"dashboard", "website"}},
+		{"is:private", []string{"website"}},
+		{"topic:CLI", []string{"dashboard"}},
+		// Free text fuzzy matches the name or a word of the description.
+		{"dsh", []string{"dashboard"}},
+		{"ling", []string{"linguist"}},
+		{"terminal", []string{"dashboard"}},
+		{"lng", []string{"linguist"}},
+		{"site lang:typescript", []string{"website"}},
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		f, err := parseFilter(tt.query, now)
+		if err != nil {
+			t.Errorf("parseFilter(%q) error: %v", tt.query, err)
+			continue
+		}
+		var got []string
+		for _, repo := range f.apply(repos) {
+			got = append(got, repo.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFilter(%q) matches %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"owner:octocat", []string{"owner:octocat: unknown qualifier"}},
+		{"stars:many", []string{"stars:many: expected a number"}},
+		{"updated:<30x", []string{"updated:<30x: expected an age"}},
+		{"updated:d", []string{"updated:d: expected an age"}},
+		{"fork:maybe", []string{"fork:maybe: expected true or false"}},
+		{"lang:go forks:x issues:>y", []string{"forks:x: expected a number", "issues:>y: expected a number"}},
+	}
+	for _, tt := range tests {
+		f, err := parseFilter(tt.query, time.Now())
+		if err == nil {
+			t.Errorf("parseFilter(%q) succeeded", tt.query)
+			continue
+		}
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != len(tt.want) {
+			t.Errorf("parseFilter(%q) error = %q, want %d errors", tt.query, err, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if !strings.HasPrefix(lines[i], want) {
+				t.Errorf("parseFilter(%q) error %d = %q, want %q", tt.query, i, lines[i], want)
+			}
+		}
+		// The valid qualifiers are kept.
+		if strings.HasPrefix(tt.query, "lang:") && len(f.predicates) != 1 {
+			t.Errorf("parseFilter(%q) kept %d predicates, want 1", tt.query, len(f.predicates))
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		term, text string
+		want       bool
+	}{
+		{"", "dashboard", true},
+		{"dash", "dashboard", true},
+		{"dbd", "dashboard", true},
+		{"bh", "dashboard", false},
+		{"dashboards", "dashboard", false},
+		{"héé", "héllo wörld é", true},
+		{"ö", "hello", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.term, tt.text); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.term, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		value string
+		want  time.Duration
+		err   bool
+	}{
+		{"12h", 12 * time.Hour, false},
+		{"30d", 30 * day, false},
+		{"2w", 14 * day, false},
+		{"6m", 180 * day, false},
+		{"1y", 365 * day, false},
+		{"0d", 0, false},
+		{"d", 0, true},
+		{"30", 0, true},
+		{"30s", 0, true},
+		{"xd", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.value)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v, want %v, error %v", tt.value, got, err, tt.want, tt.err)
+		}
+	}
+}
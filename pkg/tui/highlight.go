@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// highlight renders source code with syntax highlighting and line numbers,
+// the language is guessed from the file name and the content.
+func highlight(filePath, text string) string {
+	lexer := lexers.Match(filePath)
+	if lexer == nil {
+		lexer = lexers.Analyse(text)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	iterator, err := lexer.Tokenise(nil, strings.TrimRight(text, "\n"))
+	if err != nil {
+		return text
+	}
+	style := styles.Get("monokai")
+	formatter := formatters.Get("terminal256")
+	gutterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	// Lines are formatted one by one so multi-line tokens don't bleed into the gutter.
+	lines := chroma.SplitTokensIntoLines(iterator.Tokens())
+	width := len(fmt.Sprint(len(lines)))
+	var out strings.Builder
+	for i, tokens := range lines {
+		out.WriteString(gutterStyle.Render(fmt.Sprintf("%*d │ ", width, i+1)))
+		var line strings.Builder
+		if err := formatter.Format(&line, style, chroma.Literator(tokens...)); err != nil {
+			return text
+		}
+		out.WriteString(strings.ReplaceAll(line.String(), "\n", ""))
+		out.WriteString("\n")
+	}
+	return out.String()
+}
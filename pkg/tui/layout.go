@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"log"
+
+	"charm.land/lipgloss/v2"
+)
+
+const (
+	// minReadmeWidth is the narrowest README that still goes next to the table,
+	// narrower terminals stack the README below the table.
+	minReadmeWidth = 40
+	// minStackedHeight fits the stacked table and README boxes with a few
+	// rows of the table and of the README.
+	minStackedHeight = 2*(2+2*TopBottomPadding) + 5 + 3
+	// minDetailsHeight fits a single box with the table header and a few rows,
+	// terminals too short to stack the boxes show the table or the README.
+	minDetailsHeight = 2 + 2*TopBottomPadding + 5
+)
+
+// layout sizes the repository browser to the space the other views leave.
+func (m Model) layout() {
+	if m.browserModel == nil || m.terminalSize.width == 0 {
+		return
+	}
+	used := lipgloss.Height(m.headerView()) + lipgloss.Height(m.contributionsView()) + lipgloss.Height(m.statusView())
+	if filter := m.filterView(); filter != "" {
+		used += lipgloss.Height(filter)
+	}
+	if banner := m.panelBanner(panelRepositories); banner != "" {
+		used += lipgloss.Height(banner)
+	}
+	m.browserModel.resize(m.terminalSize.width, max(m.terminalSize.height-used, minDetailsHeight))
+}
+
+// resize fits the table and the README into width x height cells, side by
+// side when the README gets at least minReadmeWidth columns, stacked when
+// both fit in height and one at a time otherwise.
+func (m *BrowserModel) resize(width, height int) {
+	if m.width == width && m.height == height {
+		return
+	}
+	m.width, m.height = width, height
+
+	frameWidth := tableStyle.GetHorizontalFrameSize()
+	frameHeight := tableStyle.GetVerticalFrameSize()
+
+	var tableWidth, tableHeight, readmeWidth, readmeHeight int
+	if width-2*frameWidth-m.tableWidth >= minReadmeWidth {
+		m.alignment = AlignmentHorizontal
+		tableWidth = m.tableWidth
+		tableHeight = height - frameHeight
+		readmeWidth = width - tableWidth - 2*frameWidth
+		readmeHeight = height - frameHeight
+	} else if height >= minStackedHeight {
+		m.alignment = AlignmentVertical
+		tableWidth = min(m.tableWidth, width-frameWidth)
+		tableHeight = max(height*2/5-frameHeight, m.minTableHeight)
+		readmeWidth = width - frameWidth
+		readmeHeight = height - tableHeight - 2*frameHeight
+	} else {
+		m.alignment = AlignmentSingle
+		tableWidth = min(m.tableWidth, width-frameWidth)
+		tableHeight = height - frameHeight
+		readmeWidth = width - frameWidth
+		readmeHeight = height - frameHeight
+	}
+	// One line below the README is reserved for the footer.
+	readmeHeight = max(readmeHeight-1, 1)
+
+	log.Printf("[UI] Layout %dx%d: alignment %d, table %dx%d, readme %dx%d", width, height, m.alignment, tableWidth, tableHeight, readmeWidth, readmeHeight)
+
+	m.reposTable.SetWidth(tableWidth)
+	m.reposTable.SetHeight(tableHeight)
+	m.readmeViewport.SetHeight(readmeHeight)
+	if m.readmeViewport.Width() != readmeWidth {
+		m.readmeViewport.SetWidth(readmeWidth)
+		m.rerender()
+	}
+}
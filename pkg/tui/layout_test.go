@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	display "github-dashboard/pkg"
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the layout tests")
+
+// testCalendar is a year of contributions following a fixed pattern.
+func testCalendar() display.Calendar {
+	var days []display.ContributionDay
+	var total uint64
+	start := time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC)
+	for date := start; date.Year() == 2024 && !date.After(time.Date(2024, time.December, 28, 0, 0, 0, 0, time.UTC)); date = date.AddDate(0, 0, 1) {
+		count := uint64((date.YearDay() * 7) % 11)
+		if date.Weekday() == time.Sunday {
+			count = 0
+		}
+		total += count
+		days = append(days, display.ContributionDay{
+			ContributionCount: count,
+			Month:             uint8(date.Month()) - 1,
+			Weekday:           uint8(date.Weekday()),
+			Date:              date,
+		})
+	}
+	return display.Calendar{Matrix: display.MakeContributionMatrix(days), Total: total, Years: []int{2023, 2024}}
+}
+
+// testRepositories are updated relative to now so their ages stay the same.
+func testRepositories(now time.Time) github.RepositoryList {
+	names := []string{"hello-world", "dashboard", "dotfiles", "spoon-knife", "linguist", "octo-cli", "notes", "website"}
+	languages := []string{"Go", "Rust", "Shell", "HTML", "Ruby", "Go", "", "TypeScript"}
+	var repos []github.Repository
+	for i, name := range names {
+		repos = append(repos, github.Repository{
+			Owner:         "octocat",
+			Name:          name,
+			Description:   fmt.Sprintf("The %s repository", name),
+			URL:           "https://github.com/octocat/" + name,
+			Stars:         (len(names) - i) * 12,
+			Forks:         i * 3,
+			Language:      languages[i],
+			Visibility:    "PUBLIC",
+			DefaultBranch: "main",
+			CreatedAt:     now.Add(-time.Duration(i+800) * 24 * time.Hour),
+			UpdatedAt:     now.Add(-time.Duration(i+1) * 24 * time.Hour),
+		})
+	}
+	return github.RepositoryList{Repositories: repos, TotalCount: len(repos)}
+}
+
+const testReadme = `# hello-world
+
+My first repository on GitHub, see the [guide](docs/GUIDE.md).
+
+## Usage
+
+Run the program and follow the instructions.
+`
+
+// renderAt loads the dashboard from fixed messages, sends keys and renders it
+// at width x height.
+func renderAt(t *testing.T, width, height int, keys ...tea.Msg) string {
+	t.Helper()
+	now := time.Now()
+	client := github.NewClient("", github.WithCache(github.NewCache(t.TempDir(), github.DefaultCacheTTL)))
+	var model tea.Model = InitModel(Config{Username: "octocat", Client: client, Offline: true})
+
+	repos := testRepositories(now)
+	for _, msg := range []tea.Msg{
+		tea.WindowSizeMsg{Width: width, Height: height},
+		contributionsMsg{calendar: testCalendar(), fromCache: true},
+		repositoriesMsg{list: repos, fromCache: true},
+		readmeMsg{key: readmeKey(repos.Repositories[0]), readme: github.Readme{Path: "README.md", Text: testReadme}},
+	} {
+		model, _ = model.Update(msg)
+	}
+	for _, msg := range keys {
+		model, _ = model.Update(msg)
+	}
+	return ansi.Strip(model.View().Content)
+}
+
+func TestLayoutGolden(t *testing.T) {
+	focusReadme := tea.KeyPressMsg{Code: tea.KeyRight}
+	tests := []struct {
+		name          string
+		width, height int
+		keys          []tea.Msg
+	}{
+		// The minimum size.
+		{"40x22", MinWidth, MinHeight, nil},
+		// Too short to stack the table and the README, one is shown at a time.
+		{"80x24", 80, 24, nil},
+		{"80x24_readme", 80, 24, []tea.Msg{focusReadme}},
+		// Stacked, the README doesn't fit next to the table.
+		{"100x40", 100, 40, nil},
+		{"140x50", 140, 50, nil},
+		// Side by side.
+		{"200x60", 200, 60, nil},
+		{"200x60_readme", 200, 60, []tea.Msg{focusReadme}},
+	}
+	for _, tt := range tests {
+		name := tt.name
+		t.Run(name, func(t *testing.T) {
+			got := renderAt(t, tt.width, tt.height, tt.keys...)
+			path := filepath.Join("testdata", "layout_"+name+".golden")
+			if *update {
+				if err := os.MkdirAll("testdata", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v, run go test -update to create it", err)
+			}
+			if got != string(want) {
+				t.Errorf("layout at %s differs from %s:\n%s", name, path, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github-dashboard/pkg/github"
+	"github-dashboard/pkg/markdown"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/glamour/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const readmeCacheSize = 64
+
+var selectedLinkStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+
+type readmeMsg struct {
+	key    string
+	readme github.Readme
+	err    error
+}
+
+// document is a markdown file shown in the README viewport, an empty path
+// stands for the repository README.
+type document struct {
+	key     string
+	path    string
+	yOffset int
+	// tree is set when the document was left for the file tree.
+	tree bool
+}
+
+// readmeStore loads READMEs on demand and keeps the recently viewed ones in memory.
+type readmeStore struct {
+	client  *github.Client
+	cache   *lru[string, github.Readme]
+	pending map[string]bool
+}
+
+func newReadmeStore(client *github.Client) *readmeStore {
+	return &readmeStore{
+		client:  client,
+		cache:   newLRU[string, github.Readme](readmeCacheSize),
+		pending: make(map[string]bool),
+	}
+}
+
+func readmeKey(repo github.Repository) string {
+	return repo.FullName() + "@" + repo.DefaultBranch
+}
+
+func documentKey(repo github.Repository, docPath string) string {
+	if docPath == "" {
+		return readmeKey(repo)
+	}
+	return readmeKey(repo) + ":" + docPath
+}
+
+// fetch loads the document at docPath, or the README when docPath is empty.
+func (s *readmeStore) fetch(repo github.Repository, docPath string) tea.Cmd {
+	key := documentKey(repo, docPath)
+	if s.pending[key] {
+		return nil
+	}
+	s.pending[key] = true
+	client := s.client
+	return func() tea.Msg {
+		ctx := context.Background()
+		if docPath == "" {
+			readme, err := github.GetReadme(ctx, client, repo.Owner, repo.Name, repo.DefaultBranch)
+			return readmeMsg{key: key, readme: readme, err: err}
+		}
+		text, err := github.GetFile(ctx, client, repo.Owner, repo.Name, repo.DefaultBranch, docPath)
+		return readmeMsg{key: key, readme: github.Readme{Path: docPath, Text: text}, err: err}
+	}
+}
+
+func (s *readmeStore) loaded(msg readmeMsg) {
+	delete(s.pending, msg.key)
+	if msg.err == nil {
+		s.cache.add(msg.key, msg.readme)
+	}
+}
+
+// updateReadme shows the README of the selected repository, fetching it when
+// it isn't cached yet.
+func (m *BrowserModel) updateReadme(repos []github.Repository) tea.Cmd {
+	if len(repos) == 0 {
+		m.readmeKey = ""
+		m.readmeRepo = github.Repository{}
+		m.history = nil
+		m.treeShown = false
+		m.renderMarkdown("# No repositories\n\nThere is no repository to show.")
+		return nil
+	}
+	selectedIdx := m.reposTable.Cursor()
+	if selectedIdx < 0 || selectedIdx >= len(repos) {
+		return nil
+	}
+	repo := repos[selectedIdx]
+	key := readmeKey(repo)
+	if key == m.readmeKey {
+		return nil
+	}
+	m.readmeKey = key
+	m.readmeRepo = repo
+	m.history = nil
+	cmd := m.showDocument("")
+	if m.treeShown {
+		return tea.Batch(cmd, m.showTree())
+	}
+	return cmd
+}
+
+// showDocument renders the document at docPath of the selected repository,
+// fetching it when it isn't cached yet.
+func (m *BrowserModel) showDocument(docPath string) tea.Cmd {
+	m.document = document{key: documentKey(m.readmeRepo, docPath), path: docPath}
+	if readme, ok := m.readmes.cache.get(m.document.key); ok {
+		m.renderReadme(readme)
+		return nil
+	}
+	if docPath == "" {
+		m.renderMarkdown(fmt.Sprintf("# %s\n\nLoading README ...", m.readmeRepo.Name))
+	} else {
+		m.renderMarkdown(fmt.Sprintf("# %s\n\nLoading %s ...", m.readmeRepo.Name, markdown.Escape(docPath)))
+	}
+	return m.readmes.fetch(m.readmeRepo, docPath)
+}
+
+// followLink opens in-repo markdown links in the viewport and web links in
+// the browser. Other links are only shown in the footer.
+func (m *BrowserModel) followLink(link markdown.Link) tea.Cmd {
+	if link.Path == "" || link.Image || !github.IsMarkdown(link.Path) {
+		return openURL(link.URL)
+	}
+	current := m.document
+	current.yOffset = m.readmeViewport.YOffset()
+	m.history = append(m.history, current)
+	return m.showDocument(link.Path)
+}
+
+// back returns to the previously viewed document.
+func (m *BrowserModel) back() tea.Cmd {
+	if len(m.history) == 0 {
+		return nil
+	}
+	prev := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	cmd := m.showDocument(prev.path)
+	m.readmeViewport.SetYOffset(prev.yOffset)
+	m.treeShown = prev.tree
+	return cmd
+}
+
+func (m *BrowserModel) readmeLoaded(msg readmeMsg) {
+	m.readmes.loaded(msg)
+	if msg.key != m.document.key {
+		return
+	}
+	if msg.err != nil {
+		log.Printf("[UI] README %s failed: %v", msg.key, msg.err)
+		if errors.Is(msg.err, github.ErrCacheMiss) {
+			m.renderMarkdown(fmt.Sprintf("# %s not cached\n\nThis document wasn't viewed before going offline.", m.documentName()))
+			return
+		}
+		m.renderMarkdown(fmt.Sprintf("# %s failed to load\n\n%v", m.documentName(), msg.err))
+		return
+	}
+	m.renderReadme(msg.readme)
+}
+
+func (m *BrowserModel) renderReadme(readme github.Readme) {
+	switch {
+	case readme.Path == "" || readme.Text == "" && m.document.path == "":
+		m.renderMarkdown("# No README available\n\nThis repository doesn't have a README file.")
+	case readme.Text == "":
+		m.renderMarkdown(fmt.Sprintf("# %s\n\nThis file is empty, binary or doesn't exist on the default branch.", markdown.Escape(readme.Path)))
+	case github.IsMarkdown(readme.Path):
+		text, links := markdown.Rewrite(readme.Text, markdown.Base{
+			RepoURL: m.readmeRepo.URL,
+			Branch:  m.readmeRepo.DefaultBranch,
+			DocPath: readme.Path,
+		})
+		m.renderMarkdown(text)
+		m.readmeLinks = links
+	default:
+		m.readmeMarkdown = ""
+		m.setContent(highlight(readme.Path, readme.Text))
+	}
+}
+
+func (m *BrowserModel) renderMarkdown(text string) {
+	m.readmeMarkdown = text
+	// The glamour dark style has a margin of 2 on both sides.
+	width := max(m.readmeViewport.Width()-4, 10)
+	renderer, _ := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(width),
+	)
+
+	content, _ := renderer.Render(text)
+	m.setContent(content)
+}
+
+func (m *BrowserModel) setContent(content string) {
+	m.readmeContent = content
+	m.readmeLinks = nil
+	m.selectedLink = -1
+	m.readmeViewport.SetContent(content)
+	m.readmeViewport.GotoTop()
+}
+
+// rerender renders the markdown again for a new viewport width.
+func (m *BrowserModel) rerender() {
+	if m.readmeMarkdown == "" {
+		return
+	}
+	links, yOffset := m.readmeLinks, m.readmeViewport.YOffset()
+	m.renderMarkdown(m.readmeMarkdown)
+	m.readmeLinks = links
+	m.readmeViewport.SetYOffset(yOffset)
+}
+
+// selectLink moves the link selection by delta, wrapping around, and scrolls
+// the first occurrence of the link marker into view.
+func (m *BrowserModel) selectLink(delta int) {
+	if len(m.readmeLinks) == 0 {
+		return
+	}
+	if m.selectedLink < 0 && delta < 0 {
+		m.selectedLink = 0
+	}
+	m.selectedLink = (m.selectedLink + delta + len(m.readmeLinks)) % len(m.readmeLinks)
+
+	marker := fmt.Sprintf("[%d]", m.readmeLinks[m.selectedLink].Number)
+	lines := strings.Split(m.readmeContent, "\n")
+	for i, line := range lines {
+		if !strings.Contains(ansi.Strip(line), marker) {
+			continue
+		}
+		lines[i] = strings.Replace(line, marker, selectedLinkStyle.Render(marker), 1)
+		m.readmeViewport.SetContent(strings.Join(lines, "\n"))
+		m.readmeViewport.EnsureVisible(i, 0, 0)
+		return
+	}
+	m.readmeViewport.SetContent(m.readmeContent)
+}
+
+func (m *BrowserModel) documentName() string {
+	if m.document.path == "" {
+		return "README"
+	}
+	return markdown.Escape(m.document.path)
+}
+
+func (m *BrowserModel) selectedReadmeLink() (markdown.Link, bool) {
+	if m.selectedLink < 0 || m.selectedLink >= len(m.readmeLinks) {
+		return markdown.Link{}, false
+	}
+	return m.readmeLinks[m.selectedLink], true
+}
+
+// linkFooterView describes the selected link below the README.
+func (m *BrowserModel) linkFooterView() string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	var back string
+	if len(m.history) > 0 {
+		back = "  backspace: back"
+	}
+
+	link, ok := m.selectedReadmeLink()
+	if !ok {
+		if len(m.readmeLinks) == 0 {
+			return style.Render(strings.TrimSpace(back))
+		}
+		return style.Render(fmt.Sprintf("tab: select link (%d links)%s", len(m.readmeLinks), back))
+	}
+	target, action := link.URL, "enter: open"
+	switch {
+	case link.Path != "" && !link.Image && github.IsMarkdown(link.Path):
+		target = link.Path
+	case !isWebURL(link.URL):
+		action = "not a web link"
+	}
+	width := max(m.readmeViewport.Width()-len(back)-len(action)-9, 10)
+	return style.Render(fmt.Sprintf("[%d] %s  %s%s", link.Number, ansi.Truncate(target, width, "…"), action, back))
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"log"
+	"slices"
+
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// repositorySort is the order of the repository table.
+type repositorySort struct {
+	field      github.SortField
+	descending bool
+}
+
+var defaultSort = repositorySort{field: github.SortByUpdated, descending: true}
+
+// next switches to the following field, numbers and dates start with the largest.
+func (s repositorySort) next() repositorySort {
+	field := s.field.Next()
+	return repositorySort{
+		field:      field,
+		descending: field != github.SortByName && field != github.SortByLanguage,
+	}
+}
+
+func (s repositorySort) reversed() repositorySort {
+	s.descending = !s.descending
+	return s
+}
+
+// indicator returns the arrow appended to the title of the column showing field.
+func (s repositorySort) indicator(field github.SortField) string {
+	switch {
+	case s.field != field:
+		return ""
+	case s.descending:
+		return " ↓"
+	default:
+		return " ↑"
+	}
+}
+
+// sortRepositories reorders the table keeping the selected repository.
+func (m Model) sortRepositories(s repositorySort) (Model, tea.Cmd) {
+	log.Printf("[UI] Sorting repositories by %s (descending: %t)", s.field, s.descending)
+	m.sort = s
+	if m.browserModel == nil {
+		return m, nil
+	}
+
+	m.repositories.Repositories = slices.Clone(m.repositories.Repositories)
+	github.SortRepositories(m.repositories.Repositories, s.field, s.descending)
+	return m.rebuildBrowser()
+}
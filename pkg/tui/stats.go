@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	display "github-dashboard/pkg"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// statsWidth is the width of the stats shown beside the calendar.
+const statsWidth = 32
+
+const statsGap = "   "
+
+var (
+	statsLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	statsValueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+)
+
+func formatDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// formatStatsDate turns a YYYY-MM-DD or YYYY-MM date into the given layout.
+func formatStatsDate(date, from, to string) string {
+	t, err := time.Parse(from, date)
+	if err != nil {
+		return date
+	}
+	return t.Format(to)
+}
+
+// statsView lists the stats in a column placed beside the calendar.
+func statsView(stats display.Stats) string {
+	type stat struct{ label, value string }
+	lines := []stat{
+		{"Contributions", fmt.Sprint(stats.Total)},
+		{"Current streak", formatDays(stats.CurrentStreak)},
+		{"Longest streak", formatDays(stats.LongestStreak)},
+	}
+	if stats.BusiestWeekday != "" {
+		lines = append(lines, stat{"Busiest weekday", stats.BusiestWeekday})
+	}
+	if stats.BestDay.Date != "" {
+		lines = append(lines, stat{"Best day", fmt.Sprintf("%s, %d",
+			formatStatsDate(stats.BestDay.Date, "2006-01-02", "Jan 2 2006"), stats.BestDay.Count)})
+	}
+	if month, ok := bestMonth(stats); ok {
+		lines = append(lines, stat{"Best month", fmt.Sprintf("%s, %d",
+			formatStatsDate(month.Month, "2006-01", "Jan 2006"), month.Count)})
+		lines = append(lines, stat{"Months", monthBars(stats.Months, month.Count)})
+	}
+
+	var out []string
+	for _, line := range lines {
+		out = append(out, statsLabelStyle.Render(fmt.Sprintf("%-16s", line.label))+statsValueStyle.Render(line.value))
+	}
+	return strings.Join(out, "\n")
+}
+
+// statsRow summarizes the stats in a single line below narrow calendars.
+func statsRow(stats display.Stats, width int) string {
+	parts := []string{
+		fmt.Sprintf("%d contributions", stats.Total),
+		"streak " + formatDays(stats.CurrentStreak),
+		"longest " + formatDays(stats.LongestStreak),
+	}
+	if stats.BusiestWeekday != "" {
+		parts = append(parts, "busiest "+stats.BusiestWeekday)
+	}
+	if stats.BestDay.Date != "" {
+		parts = append(parts, fmt.Sprintf("best %s (%d)",
+			formatStatsDate(stats.BestDay.Date, "2006-01-02", "Jan 2"), stats.BestDay.Count))
+	}
+	return statsLabelStyle.Render(ansi.Truncate(strings.Join(parts, " · "), width, "…"))
+}
+
+var monthBarRunes = []rune("▁▂▃▄▅▆▇█")
+
+// monthBars draws the month totals as bars scaled to the busiest month.
+func monthBars(months []display.MonthTotal, busiest uint64) string {
+	var bars strings.Builder
+	for _, month := range months {
+		if month.Count == 0 {
+			bars.WriteRune(' ')
+			continue
+		}
+		bars.WriteRune(monthBarRunes[(month.Count-1)*uint64(len(monthBarRunes))/busiest])
+	}
+	return bars.String()
+}
+
+func bestMonth(stats display.Stats) (display.MonthTotal, bool) {
+	var best display.MonthTotal
+	for _, month := range stats.Months {
+		if month.Count > best.Count {
+			best = month
+		}
+	}
+	return best, best.Count > 0
+}
@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const treeCacheSize = 16
+
+type treeMsg struct {
+	key     string
+	dir     string
+	entries []github.TreeEntry
+	err     error
+}
+
+// fileTree is the lazily loaded tree of the default branch of a repository.
+type fileTree struct {
+	repo     github.Repository
+	children map[string][]github.TreeEntry
+	errs     map[string]error
+	loading  map[string]bool
+	expanded map[string]bool
+	cursor   int
+	offset   int
+}
+
+type treeRow struct {
+	entry github.TreeEntry
+	depth int
+}
+
+func newFileTree(repo github.Repository) *fileTree {
+	return &fileTree{
+		repo:     repo,
+		children: make(map[string][]github.TreeEntry),
+		errs:     make(map[string]error),
+		loading:  make(map[string]bool),
+		expanded: make(map[string]bool),
+	}
+}
+
+// treeStore keeps the trees of the recently browsed repositories, including
+// which directories are expanded.
+type treeStore struct {
+	client *github.Client
+	cache  *lru[string, *fileTree]
+}
+
+func newTreeStore(client *github.Client) *treeStore {
+	return &treeStore{
+		client: client,
+		cache:  newLRU[string, *fileTree](treeCacheSize),
+	}
+}
+
+// get returns the tree of repo, fetching its root directory when it's new.
+func (s *treeStore) get(repo github.Repository) (*fileTree, tea.Cmd) {
+	if tree, ok := s.cache.get(readmeKey(repo)); ok {
+		return tree, nil
+	}
+	tree := newFileTree(repo)
+	s.cache.add(readmeKey(repo), tree)
+	return tree, tree.fetch(s.client, "")
+}
+
+func (s *treeStore) loaded(msg treeMsg) {
+	if tree, ok := s.cache.get(msg.key); ok {
+		tree.loaded(msg)
+	}
+}
+
+func (t *fileTree) fetch(client *github.Client, dir string) tea.Cmd {
+	if t.loading[dir] {
+		return nil
+	}
+	t.loading[dir] = true
+	delete(t.errs, dir)
+	repo := t.repo
+	return func() tea.Msg {
+		entries, err := github.GetTree(context.Background(), client, repo.Owner, repo.Name, repo.DefaultBranch, dir)
+		return treeMsg{key: readmeKey(repo), dir: dir, entries: entries, err: err}
+	}
+}
+
+func (t *fileTree) loaded(msg treeMsg) {
+	delete(t.loading, msg.dir)
+	if msg.err != nil {
+		log.Printf("[UI] Tree %s:%s failed: %v", msg.key, msg.dir, msg.err)
+		t.errs[msg.dir] = msg.err
+		return
+	}
+	entries := slices.Clone(msg.entries)
+	// Directories first, like most file browsers.
+	slices.SortStableFunc(entries, func(a, b github.TreeEntry) int {
+		if a.IsDir() != b.IsDir() {
+			if a.IsDir() {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	t.children[msg.dir] = entries
+}
+
+// rows flattens the expanded part of the tree.
+func (t *fileTree) rows() []treeRow {
+	var rows []treeRow
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		for _, entry := range t.children[dir] {
+			rows = append(rows, treeRow{entry: entry, depth: depth})
+			if entry.IsDir() && t.expanded[entry.Path] {
+				walk(entry.Path, depth+1)
+			}
+		}
+	}
+	walk("", 0)
+	return rows
+}
+
+func (t *fileTree) selected() (github.TreeEntry, bool) {
+	rows := t.rows()
+	if t.cursor < 0 || t.cursor >= len(rows) {
+		return github.TreeEntry{}, false
+	}
+	return rows[t.cursor].entry, true
+}
+
+func (t *fileTree) move(delta int) {
+	t.cursor = max(min(t.cursor+delta, len(t.rows())-1), 0)
+}
+
+// toggle expands or collapses the selected directory, loading it on first expansion.
+func (t *fileTree) toggle(client *github.Client) tea.Cmd {
+	entry, ok := t.selected()
+	if !ok || !entry.IsDir() {
+		return nil
+	}
+	t.expanded[entry.Path] = !t.expanded[entry.Path]
+	if _, loaded := t.children[entry.Path]; t.expanded[entry.Path] && !loaded {
+		return t.fetch(client, entry.Path)
+	}
+	return nil
+}
+
+func (t *fileTree) view(width, height int) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dirStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("69"))
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(true)
+
+	lines := []string{titleStyle.Render(ansi.Truncate(t.repo.FullName()+" @ "+t.repo.DefaultBranch, width, "…"))}
+	height--
+
+	rows := t.rows()
+	switch {
+	case t.errs[""] != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("Failed to load files: %v", t.errs[""])))
+	case t.loading[""]:
+		lines = append(lines, hintStyle.Render("Loading files ..."))
+	case len(rows) == 0:
+		lines = append(lines, hintStyle.Render("The repository is empty."))
+	}
+
+	t.cursor = max(min(t.cursor, len(rows)-1), 0)
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+height {
+		t.offset = t.cursor - height + 1
+	}
+	for i := t.offset; i < len(rows) && i < t.offset+height; i++ {
+		row := rows[i]
+		icon, suffix := "  ", ""
+		if row.entry.IsDir() {
+			icon = "▸ "
+			if t.expanded[row.entry.Path] {
+				icon = "▾ "
+			}
+			switch {
+			case t.loading[row.entry.Path]:
+				suffix = " …"
+			case t.errs[row.entry.Path] != nil:
+				suffix = " (failed to load)"
+			}
+		}
+		line := ansi.Truncate(strings.Repeat("  ", row.depth)+icon+row.entry.Name+suffix, width, "…")
+		switch {
+		case i == t.cursor:
+			line = selectedStyle.Width(width).Render(line)
+		case row.entry.IsDir():
+			line = dirStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lipgloss.NewStyle().Width(width).Height(height + 1).Render(strings.Join(lines, "\n"))
+}
+
+// showTree shows the file tree of the selected repository in place of the README.
+func (m *BrowserModel) showTree() tea.Cmd {
+	m.treeShown = true
+	var cmd tea.Cmd
+	m.tree, cmd = m.trees.get(m.readmeRepo)
+	return cmd
+}
+
+// openTreeEntry expands the selected directory or previews the selected file.
+func (m *BrowserModel) openTreeEntry() tea.Cmd {
+	entry, ok := m.tree.selected()
+	if !ok {
+		return nil
+	}
+	if entry.IsDir() {
+		return m.tree.toggle(m.trees.client)
+	}
+	if entry.Type != "blob" {
+		return nil
+	}
+	current := m.document
+	current.yOffset = m.readmeViewport.YOffset()
+	current.tree = true
+	m.history = append(m.history, current)
+	m.treeShown = false
+	return m.showDocument(entry.Path)
+}
+
+func (m *BrowserModel) updateTree(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		m.tree.move(-1)
+	case "down", "j":
+		m.tree.move(1)
+	case "pgup":
+		m.tree.move(-m.readmeViewport.Height())
+	case "pgdown":
+		m.tree.move(m.readmeViewport.Height())
+	case "home", "g":
+		m.tree.move(-len(m.tree.rows()))
+	case "end", "G":
+		m.tree.move(len(m.tree.rows()))
+	case "enter", "space":
+		return m.openTreeEntry()
+	case "backspace":
+		m.treeShown = false
+	}
+	return nil
+}
+
+func treeFooterView() string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("enter: expand/preview  t: close")
+}
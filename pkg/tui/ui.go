@@ -1,31 +1,53 @@
 package tui
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
-	contribution "github-dashboard/pkg"
+	"github-dashboard/pkg/config"
 	"github-dashboard/pkg/github"
+	"github-dashboard/pkg/markdown"
 
 	display "github-dashboard/pkg"
 
 	"charm.land/bubbles/v2/spinner"
 	"charm.land/bubbles/v2/table"
+	"charm.land/bubbles/v2/textinput"
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
-	"charm.land/glamour/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
-type reposDataMsg struct {
-	repositories  []github.Repository
-	contributions string
+type Config struct {
+	Username string
+	Client   *github.Client
+	// MaxRepositories caps the number of fetched repositories, 0 means no cap.
+	MaxRepositories int
+	// Offline renders cached responses only, the API is never queried.
+	Offline bool
+	// RefreshInterval refetches the data periodically, 0 disables it.
+	RefreshInterval time.Duration
+	// Columns of the repository table, empty shows the default columns.
+	Columns []config.Column
+	// Range of the contribution calendar, the zero Range shows the last year.
+	Range      display.Range
+	ColorScale display.ColorScale
+	// Theme and Glyphs of the calendar, zero values keep the defaults.
+	Theme  display.Theme
+	Glyphs display.DayDisplay
 }
 
-func (d reposDataMsg) isEmpty() bool {
-	return len(d.repositories) == 0 && d.contributions == ""
-}
+type refreshTickMsg struct{}
+
+// statusTickMsg re-renders the status line so relative times stay current.
+type statusTickMsg struct{}
+
+const statusTickInterval = 30 * time.Second
 
 type terminalSize struct {
 	width  int
@@ -37,38 +59,71 @@ type Alignment int
 const (
 	AlignmentHorizontal Alignment = iota
 	AlignmentVertical
+	// AlignmentSingle shows the table, or the README while it's focused.
+	AlignmentSingle
 )
 
 type BrowserModel struct {
 	reposTable      table.Model
 	readmeViewport  viewport.Model
+	readmes         *readmeStore
+	readmeKey       string
+	readmeRepo      github.Repository
+	document        document
+	history         []document
+	trees           *treeStore
+	tree            *fileTree
+	treeShown       bool
+	readmeContent   string
+	readmeMarkdown  string
+	readmeLinks     []markdown.Link
+	selectedLink    int
 	viewportFocused bool
 	alignment       Alignment
-	tableWidth      int
-	minTableHeight  int
+	// tableWidth is the width the table needs to show every column.
+	tableWidth     int
+	minTableHeight int
+	// width and height are the space given to the browser by the layout.
+	width  int
+	height int
 }
 
 func (m BrowserModel) Init() tea.Cmd {
 	return nil
 }
 
-type errorMsg struct {
-	message string
-}
-
 type Model struct {
+	config       Config
 	browserModel *BrowserModel
 	spinner      spinner.Model
-	isLoading    bool
-	username     string
-	error        string
-	data         reposDataMsg
+	err          error
 	terminalSize terminalSize
+
+	calendar           *CalendarModel
+	contributionsState panelState
+	// breakdownShown replaces the calendar with the contribution breakdown.
+	breakdownShown bool
+	// year is picked with the year switcher, 0 shows the configured range.
+	year              int
+	repositories      github.RepositoryList
+	repositoriesState panelState
+	readmes           *readmeStore
+	trees             *treeStore
+	columns           []column
+	sort              repositorySort
+	// visible are the repositories shown in the table, narrowed by the filter.
+	visible     github.RepositoryList
+	filterInput textinput.Model
+	filtering   bool
+	filterErr   error
 }
 
 const (
-	MinWidth         = display.Width
-	MinHeight        = display.Height + TopBottomPadding*2 + 3
+	// MinWidth fits a sparkline calendar and the stacked table and README.
+	MinWidth = 40
+	// MinHeight fits the header, the calendar box with its stats row, the
+	// status line and the table or the README.
+	MinHeight        = 1 + display.Height/2 + 1 + 2 + minDetailsHeight + 1
 	TopBottomPadding = 1
 	LeftRightPadding = 2
 )
@@ -78,54 +133,67 @@ var tableStyle = lipgloss.NewStyle().
 	BorderForeground(lipgloss.Color("63")).
 	Padding(TopBottomPadding, LeftRightPadding)
 
-func InitModel(username string) tea.Model {
+func InitModel(config Config) tea.Model {
 	sp := spinner.New()
 	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("69"))
 	sp.Spinner = spinner.Points
 
+	filterInput := textinput.New()
+	filterInput.Prompt = "/ "
+	filterInput.Placeholder = "name lang:go stars:>10 updated:<30d fork:false archived:false"
+	filterInput.SetWidth(len(filterInput.Placeholder))
+
+	calendarOptions := display.CalendarOptions{
+		Scale:  config.ColorScale,
+		Theme:  config.Theme,
+		Glyphs: config.Glyphs,
+	}
+
 	return Model{
-		isLoading:    true,
-		username:     username,
-		spinner:      sp,
-		browserModel: nil,
-		error:        "",
-		data:         reposDataMsg{},
-		terminalSize: terminalSize{},
+		config:             config,
+		spinner:            sp,
+		browserModel:       nil,
+		err:                nil,
+		terminalSize:       terminalSize{},
+		calendar:           newCalendarModel(config.Client.WithCachePolicy(networkPolicy(config)), config.Username, calendarOptions),
+		contributionsState: panelState{loading: true},
+		repositoriesState:  panelState{loading: true},
+		readmes:            newReadmeStore(config.Client.WithCachePolicy(networkPolicy(config))),
+		trees:              newTreeStore(config.Client.WithCachePolicy(networkPolicy(config))),
+		columns:            tableColumns(config.Columns),
+		sort:               defaultSort,
+		filterInput:        filterInput,
 	}
 }
 
-func initBrowserModel(data reposDataMsg, size terminalSize) *BrowserModel {
-	columns := []table.Column{
-		{Title: "Name", Width: 20},
-		{Title: "Description", Width: 30},
-		{Title: "Language", Width: 12},
-		{Title: "Updated", Width: 7},
-		{Title: "Stars", Width: 5},
+func initBrowserModel(list github.RepositoryList, readmes *readmeStore, trees *treeStore, tableColumns []column, order repositorySort) *BrowserModel {
+	// The repository count goes next to the name, or to the first column without it.
+	countColumn := max(slices.IndexFunc(tableColumns, func(c column) bool { return c.name == "name" }), 0)
+	columns := []table.Column{}
+	for i, c := range tableColumns {
+		title := c.title
+		if i == countColumn {
+			title += repositoriesCount(list)
+		}
+		if c.sortable {
+			title += order.indicator(c.sort)
+		}
+		columns = append(columns, table.Column{Title: title, Width: c.width})
 	}
 
 	rows := []table.Row{}
-	for _, repo := range data.repositories {
-		rows = append(rows, table.Row{
-			repo.Name,
-			repo.Description,
-			repo.Language,
-			formatTimeAgo(repo.UpdatedAt),
-			fmt.Sprintf("%d", repo.Stars),
-		})
-	}
-
-	tableWidth := 0
-	for _, col := range columns {
-		tableWidth += col.Width
+	for _, repo := range list.Repositories {
+		row := make(table.Row, 0, len(tableColumns))
+		for _, c := range tableColumns {
+			row = append(row, c.value(repo))
+		}
+		rows = append(rows, row)
 	}
 
 	t := table.New(
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithWidth(tableWidth),
-		// TODO: remove it here
-		table.WithHeight(20),
 	)
 
 	s := table.DefaultStyles()
@@ -140,124 +208,195 @@ func initBrowserModel(data reposDataMsg, size terminalSize) *BrowserModel {
 		Bold(true)
 	t.SetStyles(s)
 
-	cellXoffset := s.Cell.GetHorizontalFrameSize()
-	log.Printf("Table width: %d", tableWidth)
-	log.Printf("Header horizontal frame size: %d", s.Header.GetHorizontalFrameSize())
-	log.Printf("Style frame size: %d", tableStyle.GetHorizontalFrameSize())
-	log.Printf("Cell X offset: %d, with all columns: %d", cellXoffset, cellXoffset*len(t.Columns()))
-	tableWidth += s.Header.GetHorizontalFrameSize() + tableStyle.GetHorizontalFrameSize() + 2*LeftRightPadding
-	log.Printf("Total table width: %d", tableWidth)
-
-	vp := viewport.New(viewport.WithWidth(80), viewport.WithHeight(21))
-	// vp := viewport.New(viewport.WithWidth(size.width), viewport.WithHeight(size.height))
+	// Every cell is padded by the cell style on top of the column width.
+	tableWidth := 0
+	for _, col := range columns {
+		tableWidth += col.Width + s.Cell.GetHorizontalFrameSize()
+	}
 
-	m := &BrowserModel{
+	return &BrowserModel{
 		reposTable:      t,
-		readmeViewport:  vp,
+		readmeViewport:  viewport.New(),
 		viewportFocused: false,
 		alignment:       AlignmentHorizontal,
 		tableWidth:      tableWidth,
+		minTableHeight:  lipgloss.Height(s.Header.Render("")) + 3,
+		readmes:         readmes,
+		trees:           trees,
 	}
-	m.updateReadme(data.repositories)
-	m.resize(size)
-	return m
-}
-
-func (m *BrowserModel) resize(term terminalSize) *BrowserModel {
-	log.Printf("Actual table width: %d", m.reposTable.Width())
-	// maxHorizontalSpace := 2 * (m.reposTable.Width() + 2*LeftRightPadding)
-	// minHorizontalSpace := int(0.75 * float64(maxHorizontalSpace))
-
-	// if term.width >= maxHorizontalSpace {
-	// 	m.readmeViewport.SetWidth(m.reposTable.Width())
-	// } else if term.width < minHorizontalSpace {
-	// 	m.alignment = AlignmentVertical
-	// 	m.readmeViewport.SetWidth(m.reposTable.Width())
-	// } else {
-	// 	width := term.width - m.reposTable.Width() - 4*LeftRightPadding
-	// 	m.readmeViewport.SetWidth(width)
-	// }
-
-	// if m.alignment == AlignmentVertical {
-	// 	m.readmeViewport.SetHeight(term.height - 3)
-	// }
-
-	return m
-}
-
-func fetchData(username string, token string) tea.Cmd {
-	return func() tea.Msg {
-		results := make(chan struct {
-			contributions string
-			repos         []github.Repository
-			err           error
-		}, 2)
-
-		go func() {
-			contributions, err := contribution.GetContributionsFromApi(token, username)
-			if err != nil {
-				results <- struct {
-					contributions string
-					repos         []github.Repository
-					err           error
-				}{"", nil, err}
-				return
-			}
-			calendar := contribution.FormatCalendar(contributions, 0, true)
-			results <- struct {
-				contributions string
-				repos         []github.Repository
-				err           error
-			}{calendar, nil, nil}
-		}()
-
-		go func() {
-			repos, err := github.GetRepositories(token, username)
-			if err != nil {
-				results <- struct {
-					contributions string
-					repos         []github.Repository
-					err           error
-				}{"", nil, err}
-				return
-			}
-			results <- struct {
-				contributions string
-				repos         []github.Repository
-				err           error
-			}{"", repos, nil}
-		}()
-		var contributions string
-		var repos []github.Repository
-
-		for i := 0; i < 2; i++ {
-			result := <-results
-			if result.err != nil {
-				return errorMsg{message: result.err.Error()}
-			}
-			if result.contributions != "" {
-				contributions = result.contributions
-			}
-			if result.repos != nil {
-				repos = result.repos
-			}
+}
+
+func repositoriesCount(list github.RepositoryList) string {
+	if !list.IsComplete() {
+		return fmt.Sprintf(" (%d/%d)", len(list.Repositories), list.TotalCount)
+	}
+	return fmt.Sprintf(" (%d)", len(list.Repositories))
+}
+
+// restoreState carries the selected repository, its README scroll position and
+// the focus over from the browser model built before a refresh.
+func (m *BrowserModel) restoreState(prev *BrowserModel, prevRepos, repos []github.Repository) tea.Cmd {
+	m.viewportFocused = prev.viewportFocused
+
+	prevCursor := prev.reposTable.Cursor()
+	if prevCursor < 0 || prevCursor >= len(prevRepos) {
+		return m.updateReadme(repos)
+	}
+	selected := prevRepos[prevCursor].FullName()
+	for i, repo := range repos {
+		if repo.FullName() != selected {
+			continue
+		}
+		m.reposTable.SetCursor(i)
+		cmd := m.updateReadme(repos)
+		if prev.readmeKey == m.readmeKey && prev.document.path != "" {
+			m.history = prev.history
+			cmd = m.showDocument(prev.document.path)
 		}
+		if prev.treeShown {
+			cmd = tea.Batch(cmd, m.showTree())
+		}
+		m.readmeViewport.SetYOffset(prev.readmeViewport.YOffset())
+		return cmd
+	}
+	m.reposTable.SetCursor(min(prevCursor, max(len(repos)-1, 0)))
+	return m.updateReadme(repos)
+}
 
-		return reposDataMsg{
-			repositories:  repos,
-			contributions: contributions,
+// networkPolicy is the cache policy for requests that should reach the API.
+func networkPolicy(config Config) github.CachePolicy {
+	if config.Offline {
+		return github.CacheOnly
+	}
+	return github.CacheDefault
+}
+
+// refreshPolicy is the cache policy for refreshes requested by the user or the timer.
+func (m Model) refreshPolicy() github.CachePolicy {
+	if m.config.Offline {
+		return github.CacheOnly
+	}
+	return github.CacheRefresh
+}
+
+func (m Model) scheduleRefresh() tea.Cmd {
+	if m.config.RefreshInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(m.config.RefreshInterval, func(time.Time) tea.Msg {
+		return refreshTickMsg{}
+	})
+}
+
+func scheduleStatusTick() tea.Cmd {
+	return tea.Tick(statusTickInterval, func(time.Time) tea.Msg {
+		return statusTickMsg{}
+	})
+}
+
+func (m *Model) state(p panel) *panelState {
+	switch p {
+	case panelContributions:
+		return &m.contributionsState
+	case panelRepositories:
+		return &m.repositoriesState
+	}
+	panic(fmt.Sprintf("unknown panel %d", p))
+}
+
+func (m Model) isLoading() bool {
+	return m.contributionsState.loading || m.repositoriesState.loading
+}
+
+// failedPanels lists panels whose most recent fetch failed.
+func (m Model) failedPanels() panel {
+	var failed panel
+	for _, p := range allPanels.panels() {
+		if m.state(p).err != nil {
+			failed |= p
+		}
+	}
+	return failed
+}
+
+// dataError returns an error when every panel failed and there is nothing to render.
+func (m Model) dataError() error {
+	for _, p := range allPanels.panels() {
+		state := m.state(p)
+		if state.err == nil || state.loaded || state.loading {
+			return nil
 		}
 	}
+	return cmp.Or(m.repositoriesState.err, m.contributionsState.err)
+}
+
+// refresh refetches the panels which aren't being loaded already.
+func (m Model) refresh(panels panel, policy github.CachePolicy) (Model, tea.Cmd) {
+	cmds := []tea.Cmd{}
+	if !m.isLoading() {
+		cmds = append(cmds, m.spinner.Tick)
+	}
+	for _, p := range panels.panels() {
+		state := m.state(p)
+		if state.loading {
+			continue
+		}
+		log.Printf("[UI] Refreshing %s", p)
+		state.loading = true
+		cmds = append(cmds, fetchPanel(m.fetchConfig(), policy, p))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// panelLoaded records the outcome of a panel fetch and reports whether the
+// fetched data should be shown. Cached data is shown right away and refreshed
+// from the API in the background, a cache miss simply waits for the API.
+func (m *Model) panelLoaded(p panel, err error, fromCache bool, fetchedAt time.Time) (bool, tea.Cmd) {
+	state := m.state(p)
+	state.loading = false
+
+	var cmd tea.Cmd
+	if fromCache && !m.config.Offline {
+		state.loading = true
+		cmd = fetchPanel(m.fetchConfig(), github.CacheDefault, p)
+		if err != nil {
+			return false, cmd
+		}
+		log.Printf("[UI] Rendered cached %s, refreshing in background", p)
+	}
+	if err != nil {
+		log.Printf("[UI] %s failed: %v", p, err)
+		state.err = err
+		return false, nil
+	}
+
+	state.err = nil
+	state.loaded = true
+	state.fromCache = fromCache
+	if !fromCache {
+		state.updatedAt = fetchedAt
+	}
+	return true, cmd
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		fetchData(m.username, contribution.GetToken()),
+		m.calendar.Init(),
+		// Render cached data instantly, the API is queried once it's shown.
+		fetchPanels(m.config, github.CacheOnly, allPanels),
+		m.scheduleRefresh(),
+		scheduleStatusTick(),
 	)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.layout()
+	return m, cmd
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		log.Printf("[UI] Window resize: %dx%d (min: %dx%d)", msg.Width, msg.Height, MinWidth, MinHeight)
@@ -265,64 +404,184 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.terminalSize.width = msg.Width
 		if msg.Width < MinWidth || msg.Height < MinHeight {
 			log.Printf("[UI] Window too small - setting error")
-			m.error = "Terminal too small"
-			m.isLoading = false
+			m.err = errTerminalTooSmall
 			return m, nil
-		} else {
-			if m.browserModel == nil && !m.data.isEmpty() {
-				m.browserModel = initBrowserModel(m.data, m.terminalSize)
-			}
-			m.error = ""
 		}
-		if !m.isLoading && m.error == "" {
-			m.browserModel = m.browserModel.resize(m.terminalSize)
+		if errors.Is(m.err, errTerminalTooSmall) {
+			m.err = nil
 		}
 		return m, nil
 	case tea.KeyMsg:
 		log.Printf("[UI] Key pressed: %s", msg.String())
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		if m.calendar.focused && m.err == nil {
+			switch msg.String() {
+			case "q", "ctrl+c", "r", "R", "[", "]", "i":
+			default:
+				var cmd tea.Cmd
+				m.calendar, cmd = m.calendar.Update(msg)
+				return m, cmd
+			}
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
-		default:
-			if !m.isLoading && m.error == "" {
-				log.Printf("[UI] Forwarding key to browser model")
-				cmd := m.browserModel.update(msg, m.data.repositories)
-				return m, cmd
+		case "r":
+			if m.err != nil && !isRetryable(m.err) {
+				return m, nil
+			}
+			if m.dataError() != nil {
+				log.Printf("[UI] Retrying after error: %v", m.dataError())
+				return m.refresh(allPanels, networkPolicy(m.config))
+			}
+			return m.refresh(allPanels, m.refreshPolicy())
+		case "R":
+			if m.err == nil && m.failedPanels() != 0 {
+				return m.refresh(m.failedPanels(), m.refreshPolicy())
+			}
+		case "s", "S":
+			if m.err == nil && m.browserModel != nil && !m.browserModel.viewportFocused {
+				if msg.String() == "S" {
+					return m.sortRepositories(m.sort.reversed())
+				}
+				return m.sortRepositories(m.sort.next())
 			}
+		case "[", "]":
+			if m.err == nil {
+				return m.switchYear(msg.String() == "[")
+			}
+		case "c":
+			if m.err == nil && m.contributionsState.loaded && len(m.calendar.days) > 0 {
+				m.calendar.focused = true
+				m.breakdownShown = false
+				return m, nil
+			}
+		case "i":
+			if m.err == nil && m.contributionsState.loaded {
+				m.breakdownShown = !m.breakdownShown
+				m.calendar.focused = false
+				return m, nil
+			}
+		case "/":
+			if m.err == nil && m.browserModel != nil && !m.browserModel.viewportFocused {
+				m.filtering = true
+				return m, m.filterInput.Focus()
+			}
+		}
+		if m.browserModel != nil && m.err == nil {
+			log.Printf("[UI] Forwarding key to browser model")
+			cmd := m.browserModel.update(msg, m.visible.Repositories)
+			return m, cmd
+		}
+		return m, nil
+	case contributionsMsg:
+		log.Printf("[UI] Received contributions message")
+		if msg.rng != m.contributionRange() {
+			log.Printf("[UI] Dropping contributions of %s", msg.rng)
 			return m, nil
 		}
-	case reposDataMsg:
-		log.Printf("[UI] Received repos data message")
-		m.data = msg
-		if m.error == "" {
-			m.browserModel = initBrowserModel(msg, m.terminalSize)
-			m.isLoading = false
+		apply, cmd := m.panelLoaded(panelContributions, msg.err, msg.fromCache, msg.fetchedAt)
+		if apply {
+			m.calendar.setData(msg.calendar)
+		}
+		return m, cmd
+	case repositoriesMsg:
+		log.Printf("[UI] Received repositories message")
+		apply, cmd := m.panelLoaded(panelRepositories, msg.err, msg.fromCache, msg.fetchedAt)
+		if apply {
+			m.repositories = msg.list
+			github.SortRepositories(m.repositories.Repositories, m.sort.field, m.sort.descending)
+			var browserCmd tea.Cmd
+			m, browserCmd = m.rebuildBrowser()
+			cmd = tea.Batch(cmd, browserCmd)
+		}
+		return m, cmd
+	case readmeMsg:
+		if m.browserModel != nil {
+			m.browserModel.readmeLoaded(msg)
+		} else {
+			m.readmes.loaded(msg)
 		}
 		return m, nil
+	case treeMsg:
+		m.trees.loaded(msg)
+		return m, nil
+	case dayMsg:
+		var cmd tea.Cmd
+		m.calendar, cmd = m.calendar.Update(msg)
+		return m, cmd
+	case refreshTickMsg:
+		m, cmd := m.refresh(allPanels, m.refreshPolicy())
+		return m, tea.Batch(cmd, m.scheduleRefresh())
+	case statusTickMsg:
+		return m, scheduleStatusTick()
 	case spinner.TickMsg:
-		if m.isLoading {
+		if m.isLoading() {
 			log.Printf("[UI] Spinner tick")
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
 		}
 		return m, nil
-	case errorMsg:
-		log.Printf("[UI] Error message: %s", msg.message)
-		m.error = msg.message
-		m.isLoading = false
-		return m, nil
+	}
+	if m.filtering {
+		// Cursor blinking of the filter prompt.
+		var cmd tea.Cmd
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		return m, cmd
 	}
 	return m, nil
 }
 
+// rebuildBrowser shows the filtered repositories, keeping the selection when
+// it's still visible.
+func (m Model) rebuildBrowser() (Model, tea.Cmd) {
+	prevVisible := m.visible.Repositories
+	m.visible = m.filteredRepositories()
+	prev := m.browserModel
+	m.browserModel = initBrowserModel(m.visible, m.readmes, m.trees, m.columns, m.sort)
+	// READMEs are rendered for the final width right away.
+	m.layout()
+	if prev != nil {
+		return m, m.browserModel.restoreState(prev, prevVisible, m.visible.Repositories)
+	}
+	return m, m.browserModel.updateReadme(m.visible.Repositories)
+}
+
 func (m *BrowserModel) update(msg tea.KeyMsg, repos []github.Repository) tea.Cmd {
 	switch msg.String() {
 	case "esc", "left", "h", "right", "l":
 		m.viewportFocused = !m.viewportFocused
 		return nil
+	case "t":
+		if m.treeShown || m.readmeKey == "" {
+			m.treeShown = false
+			return nil
+		}
+		m.viewportFocused = true
+		return m.showTree()
 	default:
+		if m.viewportFocused && m.treeShown {
+			return m.updateTree(msg)
+		}
 		if m.viewportFocused {
+			switch msg.String() {
+			case "tab":
+				m.selectLink(1)
+				return nil
+			case "shift+tab":
+				m.selectLink(-1)
+				return nil
+			case "enter":
+				if link, ok := m.selectedReadmeLink(); ok {
+					return m.followLink(link)
+				}
+				return nil
+			case "backspace":
+				return m.back()
+			}
 			var cmd tea.Cmd
 			m.readmeViewport, cmd = m.readmeViewport.Update(msg)
 			return cmd
@@ -330,91 +589,214 @@ func (m *BrowserModel) update(msg tea.KeyMsg, repos []github.Repository) tea.Cmd
 		} else {
 			var cmd tea.Cmd
 			m.reposTable, cmd = m.reposTable.Update(msg)
-			m.updateReadme(repos)
-			return cmd
+			return tea.Batch(cmd, m.updateReadme(repos))
 		}
 	}
 }
 
-func (m *BrowserModel) updateReadme(repos []github.Repository) {
-	if len(repos) == 0 {
-		return
+func (m Model) View() tea.View {
+	err := m.err
+	if err == nil {
+		err = m.dataError()
+	}
+	if err != nil {
+		v := tea.NewView(errorView(err))
+		v.AltScreen = true
+		return v
 	}
 
-	selectedIdx := m.reposTable.Cursor()
-	if selectedIdx >= len(repos) {
-		return
+	var details string
+	if m.browserModel != nil {
+		details = m.browserModel.view(m.panelBanner(panelRepositories))
+		if filter := m.filterView(); filter != "" {
+			details = lipgloss.JoinVertical(lipgloss.Left, filter, details)
+		}
+	} else if banner := m.panelBanner(panelRepositories); banner != "" {
+		details = banner
+	} else {
+		details = m.loadingView(panelRepositories)
 	}
 
-	// Get the README content
-	readme := repos[selectedIdx].Readme
-	if readme == "" {
-		readme = "# No README available\n\nThis repository doesn't have a README file."
+	v := tea.NewView(lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.headerView(),
+		m.contributionsView(),
+		details,
+		m.statusView(),
+	))
+	v.AltScreen = true
+	return v
+}
+
+func (m Model) headerView() string {
+	nameStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	linkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	header := fmt.Sprintf(" %s  %s  %s", nameStyle.Render(m.config.Username), linkStyle.Render(m.config.Client.WebURL(m.config.Username)),
+		linkStyle.Render("contributions: "+m.rangeLabel()))
+	return m.truncate(header)
+}
+
+// truncate cuts a line to the terminal width.
+func (m Model) truncate(line string) string {
+	if m.terminalSize.width == 0 {
+		return line
 	}
+	return ansi.Truncate(line, m.terminalSize.width, "…")
+}
 
-	// Render markdown
-	width := m.readmeViewport.Width() - 4 // TODO: Account for padding
-	renderer, _ := glamour.NewTermRenderer(
-		glamour.WithStandardStyle("dark"),
-		glamour.WithWordWrap(width),
-	)
+func (m Model) loadingView(p panel) string {
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render
+	return fmt.Sprintf(" %s  %s", m.spinner.View(), textStyle(p.String()+" loading ..."))
+}
+
+func (m Model) contributionsView() string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.BlockBorder()).
+		BorderForeground(lipgloss.Color("240"))
+
+	// The calendar degrades to compact modes on narrow terminals.
+	width := display.Width
+	if m.terminalSize.width > 0 {
+		width = min(width, m.terminalSize.width-style.GetHorizontalFrameSize())
+	}
 
-	content, _ := renderer.Render(readme)
-	m.readmeViewport.SetContent(content)
-	m.readmeViewport.GotoTop()
+	var calendar string
+	if m.contributionsState.loaded {
+		if m.breakdownShown {
+			calendar = breakdownView(m.calendar.data.Breakdown, m.config.Username, m.config.Theme, width)
+		} else {
+			m.calendar.SetSize(width, m.terminalSize.width-style.GetHorizontalFrameSize())
+			calendar = m.calendar.View()
+		}
+	}
+	content := calendar
+	if !m.contributionsState.loaded {
+		// Keep the calendar footprint so the layout doesn't jump once it arrives.
+		style = style.Width(width).Height(display.Height / 2)
+		content = m.loadingView(panelContributions)
+	}
+	if banner := m.panelBanner(panelContributions); banner != "" {
+		content = banner
+		if m.contributionsState.loaded {
+			content = calendar + "\n" + banner
+		}
+	}
+	// lipgloss.PlaceHorizontal(contribution.Width, lipgloss.Center, contributions),
+	return style.Render(content)
 }
 
-func (m Model) View() tea.View {
-	if m.error != "" {
-		textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render
-		v := tea.NewView(fmt.Sprintf("\n  Error: %s\n\n  Press 'q' to quit\n", textStyle(m.error)))
-		v.AltScreen = true
-		return v
+// panelBanner renders the inline error of a panel which failed to load.
+func (m Model) panelBanner(p panel) string {
+	state := m.state(p)
+	if state.err == nil {
+		return ""
 	}
-	if m.isLoading {
-		textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render
-		v := tea.NewView(fmt.Sprintf("\n %s  %s\n", m.spinner.View(), textStyle("Repositories loading ...")))
-		v.AltScreen = true
-		return v
+	if state.loading {
+		return m.loadingView(p)
 	}
-	v := m.browserModel.view(m.data.contributions)
-	v.AltScreen = true
-	return v
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	banner := textStyle.Render(fmt.Sprintf(" %s failed to load: %v", p, state.err))
+	if guidance := errorGuidance(state.err); guidance != "" {
+		banner += "\n " + hintStyle.Render(guidance)
+	}
+	return banner + "\n " + hintStyle.Render("Press 'R' to retry failed panels")
 }
 
-func (m BrowserModel) view(contributions string) tea.View {
+func (m BrowserModel) view(repositoriesBanner string) string {
 	style := tableStyle
 	if m.viewportFocused {
 		style = style.BorderStyle(lipgloss.ThickBorder())
 	}
 
-	tableView := tableStyle.Render(m.reposTable.View())
-	view := style.Render(m.readmeViewport.View())
+	// The table header isn't cut to the width of narrow tables.
+	tableView := tableStyle.Render(lipgloss.NewStyle().MaxWidth(m.reposTable.Width()).Render(m.reposTable.View()))
+	readme, footer := m.readmeViewport.View(), m.linkFooterView()
+	if m.treeShown {
+		readme, footer = m.tree.view(m.readmeViewport.Width(), m.readmeViewport.Height()), treeFooterView()
+	}
+	if !m.viewportFocused {
+		footer = ""
+	}
+	// The footer line is always reserved so the layout doesn't jump on focus changes.
+	readme = lipgloss.JoinVertical(lipgloss.Left, readme, ansi.Truncate(footer, m.readmeViewport.Width(), "…"))
+	view := style.Render(readme)
 
 	var details string
-	if m.alignment == AlignmentHorizontal {
+	switch {
+	case m.alignment == AlignmentHorizontal:
 		details = lipgloss.JoinHorizontal(
 			lipgloss.Top,
 			tableView,
 			view,
 		)
-	} else {
+	case m.alignment == AlignmentVertical:
 		details = lipgloss.JoinVertical(
 			lipgloss.Left,
 			tableView,
 			view,
 		)
+	case m.viewportFocused:
+		details = view
+	default:
+		details = tableView
 	}
-	style = lipgloss.NewStyle().
-		BorderStyle(lipgloss.BlockBorder()).
-		BorderForeground(lipgloss.Color("240"))
-	container := lipgloss.JoinVertical(
-		lipgloss.Left,
-		// lipgloss.PlaceHorizontal(contribution.Width, lipgloss.Center, contributions),
-		style.Render(contributions),
-		details,
-	)
-	return tea.NewView(container)
+	if repositoriesBanner != "" {
+		details = lipgloss.JoinVertical(lipgloss.Left, repositoriesBanner, details)
+	}
+	return details
+}
+
+func (m Model) statusView() string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	warningStyle := style.Foreground(lipgloss.Color("208"))
+
+	var quota string
+	rateLimit := m.config.Client.RateLimit()
+	switch {
+	case m.config.Offline:
+		quota = style.Render(" Offline, showing cached data")
+	case !rateLimit.IsKnown():
+		quota = style.Render(" API quota: unknown")
+	case rateLimit.Remaining < rateLimit.Limit/10:
+		quota = warningStyle.Render(fmt.Sprintf(" API quota: %d/%d, resets in %s", rateLimit.Remaining, rateLimit.Limit, formatDuration(time.Until(rateLimit.ResetAt))))
+	default:
+		quota = style.Render(fmt.Sprintf(" API quota: %d/%d, resets in %s", rateLimit.Remaining, rateLimit.Limit, formatDuration(time.Until(rateLimit.ResetAt))))
+	}
+
+	var updatedAt time.Time
+	fromCache := false
+	for _, p := range allPanels.panels() {
+		state := m.state(p)
+		if !state.loaded {
+			continue
+		}
+		fromCache = fromCache || state.fromCache
+		if updatedAt.IsZero() || state.updatedAt.Before(updatedAt) {
+			updatedAt = state.updatedAt
+		}
+	}
+	status := "press 'r' to refresh"
+	if m.isLoading() {
+		status = "refreshing ..."
+	}
+	switch {
+	case fromCache:
+		status = "Cached data, " + status
+	case !updatedAt.IsZero():
+		status = "Updated " + formatTimeAgo(updatedAt) + ", " + status
+	}
+	return m.truncate(quota + style.Render("  |  "+status))
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", max(int(d.Seconds()), 0))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
 }
 
 func formatTimeAgo(t time.Time) string {
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	display "github-dashboard/pkg"
+	"github-dashboard/pkg/github"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// contributionRange is the range of the shown contribution calendar.
+func (m Model) contributionRange() display.Range {
+	if m.year != 0 {
+		return display.YearRange(m.year, time.Now())
+	}
+	return m.config.Range
+}
+
+// fetchConfig is the config fetching the shown contribution range.
+func (m Model) fetchConfig() Config {
+	config := m.config
+	config.Range = m.contributionRange()
+	return config
+}
+
+func (m Model) rangeLabel() string {
+	if m.year != 0 {
+		return strconv.Itoa(m.year)
+	}
+	return m.config.Range.String()
+}
+
+// nextYear picks the previous or the next year the user contributed in. The
+// first step back from the configured range shows the year the range ends in,
+// stepping past the most recent year returns to the configured range.
+func (m Model) nextYear(older bool, now time.Time) (int, bool) {
+	years := m.calendar.data.Years
+	if older {
+		bound := m.year
+		if bound == 0 {
+			end := now
+			if !m.config.Range.IsZero() {
+				end = m.config.Range.To
+			}
+			bound = end.Year() + 1
+		}
+		if len(years) == 0 {
+			return bound - 1, true
+		}
+		year := 0
+		for _, y := range years {
+			if y < bound && y > year {
+				year = y
+			}
+		}
+		return year, year != 0
+	}
+
+	if m.year == 0 {
+		return 0, false
+	}
+	year := m.year + 1
+	if len(years) > 0 {
+		year = 0
+		for _, y := range years {
+			if y > m.year && (year == 0 || y < year) {
+				year = y
+			}
+		}
+	}
+	if year == 0 || year > now.Year() {
+		return 0, true
+	}
+	return year, true
+}
+
+// switchYear shows the calendar of the previous or the next year.
+func (m Model) switchYear(older bool) (Model, tea.Cmd) {
+	year, ok := m.nextYear(older, time.Now())
+	if !ok || year == m.year {
+		return m, nil
+	}
+	m.year = year
+	log.Printf("[UI] Switching contributions to %s", m.rangeLabel())
+
+	var cmds []tea.Cmd
+	if !m.isLoading() {
+		cmds = append(cmds, m.spinner.Tick)
+	}
+	m.contributionsState = panelState{loading: true}
+	// Cached calendars are shown instantly and refreshed by panelLoaded.
+	cmds = append(cmds, fetchPanel(m.fetchConfig(), github.CacheOnly, panelContributions))
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	display "github-dashboard/pkg"
+)
+
+func TestNextYear(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	configured := display.Range{
+		From: time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2022, time.June, 30, 23, 59, 59, 0, time.UTC),
+	}
+	tests := []struct {
+		name     string
+		years    []int
+		year     int
+		r        display.Range
+		older    bool
+		want     int
+		wantStep bool
+	}{
+		{name: "back from the last year", years: []int{2024, 2022, 2021}, older: true, want: 2024, wantStep: true},
+		{name: "back skips quiet years", years: []int{2024, 2022, 2021}, year: 2024, older: true, want: 2022, wantStep: true},
+		{name: "back from the oldest year", years: []int{2024, 2022, 2021}, year: 2021, older: true},
+		{name: "back from a configured range", years: []int{2024, 2022, 2021}, r: configured, older: true, want: 2022, wantStep: true},
+		{name: "back without contribution years", year: 2020, older: true, want: 2019, wantStep: true},
+		{name: "forward skips quiet years", years: []int{2024, 2022, 2021}, year: 2022, want: 2024, wantStep: true},
+		{name: "forward past the latest year", years: []int{2024, 2022, 2021}, year: 2024, want: 0, wantStep: true},
+		{name: "forward without contribution years", year: 2022, want: 2023, wantStep: true},
+		{name: "forward past the current year", year: 2024, want: 0, wantStep: true},
+		{name: "forward from the configured range", years: []int{2024, 2022, 2021}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{
+				config:   Config{Range: tt.r},
+				calendar: &CalendarModel{data: display.Calendar{Years: tt.years}},
+				year:     tt.year,
+			}
+			got, ok := m.nextYear(tt.older, now)
+			if got != tt.want || ok != tt.wantStep {
+				t.Errorf("nextYear(%v) = %d, %v, want %d, %v", tt.older, got, ok, tt.want, tt.wantStep)
+			}
+		})
+	}
+}
@@ -1,7 +1,18 @@
 package contribution
 
-import "os"
+import (
+	"os"
+
+	"github-dashboard/pkg/github"
+)
 
 func GetToken() string {
 	return os.Getenv("GITHUB_TOKEN")
 }
+
+func GetAPIURL() string {
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		return apiURL
+	}
+	return github.DefaultAPIURL
+}